@@ -1,8 +1,14 @@
 package fill
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
+	"strings"
 
+	"github.com/thinkgos/tdengine-gorm/clause/fn"
+	"github.com/thinkgos/tdengine-gorm/clause/window"
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
@@ -15,18 +21,22 @@ const (
 	FillNull   FillType = "NULL"
 	FillLinear FillType = "LINEAR"
 	FillNext   FillType = "NEXT"
+	// FillValueF same as FillValue, but force filling in non-window queries.
+	FillValueF FillType = "VALUE_F"
+	// FillNullF same as FillNull, but force filling in non-window queries.
+	FillNullF FillType = "NULL_F"
 )
 
 type Fill struct {
 	Type  FillType
-	Value float64 // only support Type = FillValue.
+	Value float64 // only support Type = FillValue or FillValueF
 }
 
 // Build [FILL(fill_mod_and_val)]
 func (f Fill) Build(builder clause.Builder) {
 	_, _ = builder.WriteString("(")
 	_, _ = builder.WriteString(string(f.Type))
-	if f.Type == FillValue {
+	if f.Type == FillValue || f.Type == FillValueF {
 		_ = builder.WriteByte(',')
 		_, _ = builder.WriteString(strconv.FormatFloat(f.Value, 'g', -1, 64))
 	}
@@ -40,3 +50,164 @@ func (f Fill) Name() string {
 func (f Fill) MergeClause(c *clause.Clause) {
 	c.Expression = f
 }
+
+// Values fill with one value per aggregate column, support numbers, strings, booleans and nil (NULL).
+type Values struct {
+	Type   FillType // only support Type = FillValue or FillValueF
+	Values []any
+}
+
+// SetValues create a FILL(VALUE, val1[, val2] ...) clause
+func SetValues(values ...any) Values {
+	return Values{Type: FillValue, Values: values}
+}
+
+// SetValuesF create a FILL(VALUE_F, val1[, val2] ...) clause
+func SetValuesF(values ...any) Values {
+	return Values{Type: FillValueF, Values: values}
+}
+
+// Build [FILL(VALUE, val1[, val2] ...)]
+func (f Values) Build(builder clause.Builder) {
+	if f.Type != FillValue && f.Type != FillValueF {
+		_ = builder.AddError(fmt.Errorf("fill: values require %s or %s, got %q", FillValue, FillValueF, f.Type))
+		return
+	}
+	if len(f.Values) == 0 {
+		_ = builder.AddError(errors.New("fill: values required"))
+		return
+	}
+	if stmt, ok := builder.(*gorm.Statement); ok {
+		if n, ok := countAggregates(stmt); ok && n != len(f.Values) {
+			_ = builder.AddError(fmt.Errorf("fill: got %d values for %d aggregate columns", len(f.Values), n))
+		}
+	}
+	_, _ = builder.WriteString("(")
+	_, _ = builder.WriteString(string(f.Type))
+	for _, v := range f.Values {
+		_ = builder.WriteByte(',')
+		switch v.(type) {
+		case nil:
+			_, _ = builder.WriteString("NULL")
+		case bool, string,
+			int, int8, int16, int32, int64,
+			uint, uint8, uint16, uint32, uint64,
+			float32, float64:
+			builder.AddVar(builder, v)
+		default:
+			_ = builder.AddError(fmt.Errorf("fill: unsupported value type %T", v))
+		}
+	}
+	_ = builder.WriteByte(')')
+}
+
+func (f Values) Name() string {
+	return "FILL"
+}
+
+func (f Values) MergeClause(c *clause.Clause) {
+	c.Expression = f
+}
+
+// aggregates the aggregate and selector functions whose output columns are filled.
+var aggregates = map[string]struct{}{
+	"apercentile": {}, "avg": {}, "bottom": {}, "count": {}, "elapsed": {}, "first": {},
+	"histogram": {}, "hyperloglog": {}, "interp": {}, "irate": {}, "last": {}, "last_row": {},
+	"leastsquares": {}, "max": {}, "min": {}, "mode": {}, "percentile": {}, "spread": {},
+	"stddev": {}, "sum": {}, "top": {}, "twa": {},
+}
+
+// countAggregates count the aggregate columns of SELECT clause,
+// returns false if the columns can not be told.
+func countAggregates(stmt *gorm.Statement) (int, bool) {
+	c, ok := stmt.Clauses["SELECT"]
+	if !ok {
+		return 0, false
+	}
+	var (
+		sqls []string
+		vars []any
+	)
+	switch sel := c.Expression.(type) {
+	case clause.Expr: // merged from select with vars, e.g. Select("?", fn.Avg("v"))
+		sqls, vars = append(sqls, sel.SQL), sel.Vars
+	case clause.Select:
+		if expr, ok := sel.Expression.(clause.Expr); ok {
+			sqls, vars = append(sqls, expr.SQL), expr.Vars
+		} else if sel.Expression != nil {
+			return 0, false
+		}
+		for _, column := range sel.Columns {
+			if column.Raw {
+				sqls = append(sqls, column.Name)
+			}
+		}
+	default:
+		return 0, false
+	}
+	if len(sqls) > 0 && len(sqls[0]) > len("DISTINCT ") && strings.EqualFold(sqls[0][:len("DISTINCT ")], "DISTINCT ") {
+		sqls[0] = sqls[0][len("DISTINCT "):]
+	}
+	count := 0
+	for _, s := range sqls {
+		for _, part := range splitTopLevel(s) {
+			// the var of the column written as a placeholder, e.g. Select("?", fn.Avg("v")).
+			var v any
+			if strings.HasPrefix(strings.TrimSpace(part), "?") && len(vars) > 0 {
+				v = vars[0]
+			}
+			vars = vars[min(strings.Count(part, "?"), len(vars)):]
+			switch v := v.(type) {
+			case fn.Func:
+				if _, ok := aggregates[strings.ToLower(v.FuncName())]; !ok {
+					return 0, false
+				}
+				count++
+				continue
+			case clause.Column, window.PseudoColumn:
+				continue
+			case clause.Expression:
+				return 0, false
+			}
+			i := strings.IndexByte(part, '(')
+			if i < 0 {
+				continue
+			}
+			// other function calls, e.g. CAST(AVG(`v`) AS INT), can not be told.
+			if _, ok := aggregates[strings.ToLower(strings.TrimSpace(part[:i]))]; !ok {
+				return 0, false
+			}
+			count++
+		}
+	}
+	return count, count > 0
+}
+
+// splitTopLevel split by comma which is not in parentheses or quotes.
+func splitTopLevel(s string) []string {
+	var (
+		parts []string
+		depth int
+		quote byte
+		start int
+	)
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"' || ch == '`':
+			quote = ch
+		case ch == '(':
+			depth++
+		case ch == ')':
+			depth--
+		case ch == ',' && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
@@ -4,9 +4,11 @@ import (
 	"testing"
 
 	"github.com/thinkgos/tdengine-gorm/clause/fill"
+	"github.com/thinkgos/tdengine-gorm/clause/fn"
 	"github.com/thinkgos/tdengine-gorm/clause/tests"
 	"github.com/thinkgos/tdengine-gorm/clause/window"
 
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
@@ -28,6 +30,59 @@ func Test_Fill(t *testing.T) {
 			Result: []string{"SELECT avg(`t_1`.`value`) FROM `t_1` INTERVAL(10m) FILL (VALUE,12)"},
 			Vars:   nil,
 		},
+		{
+			Name: "multi values",
+			Clauses: []clause.Interface{
+				clause.Select{Columns: []clause.Column{{Name: "_wstart,avg(`value`),last(`name`),last(`on`),max(`value`)", Raw: true}}},
+				clause.From{Tables: []clause.Table{{Name: "t_1"}}},
+				window.SetInterval(window.Duration{Value: 10, Unit: window.Minute}),
+				fill.SetValues(1.5, "none", true, nil),
+			},
+			Result: []string{"SELECT _wstart,avg(`value`),last(`name`),last(`on`),max(`value`) FROM `t_1` INTERVAL(10m) FILL (VALUE,?,?,?,NULL)"},
+			Vars:   [][][]any{{{1.5, "none", true}}},
+		},
+		{
+			Name: "typed functions",
+			Clauses: []clause.Interface{
+				clause.Select{Expression: clause.Expr{SQL: "?,?,?", Vars: []any{window.WStart, fn.Avg("a"), fn.Max("b")}}},
+				clause.From{Tables: []clause.Table{{Name: "t_1"}}},
+				window.SetInterval(window.Duration{Value: 10, Unit: window.Minute}),
+				fill.SetValues(1, 2),
+			},
+			Result: []string{"SELECT _wstart,AVG(`a`),MAX(`b`) FROM `t_1` INTERVAL(10m) FILL (VALUE,?,?)"},
+			Vars:   [][][]any{{{1, 2}}},
+		},
+		{
+			Name: "unknown function",
+			Clauses: []clause.Interface{
+				clause.Select{Columns: []clause.Column{{Name: "_wstart,CAST(avg(`value`) AS INT),max(`value`)", Raw: true}}},
+				clause.From{Tables: []clause.Table{{Name: "t_1"}}},
+				window.SetInterval(window.Duration{Value: 10, Unit: window.Minute}),
+				fill.SetValues(1),
+			},
+			Result: []string{"SELECT _wstart,CAST(avg(`value`) AS INT),max(`value`) FROM `t_1` INTERVAL(10m) FILL (VALUE,?)"},
+			Vars:   [][][]any{{{1}}},
+		},
+		{
+			Name: "value_f",
+			Clauses: []clause.Interface{
+				clause.Select{Columns: []clause.Column{{Name: "INTERP(`value`)", Raw: true}}},
+				clause.From{Tables: []clause.Table{{Name: "t_1"}}},
+				fill.SetValuesF(0),
+			},
+			Result: []string{"SELECT INTERP(`value`) FROM `t_1` FILL (VALUE_F,?)"},
+			Vars:   [][][]any{{{0}}},
+		},
+		{
+			Name: "null_f",
+			Clauses: []clause.Interface{
+				clause.Select{Columns: []clause.Column{{Name: "INTERP(`value`)", Raw: true}}},
+				clause.From{Tables: []clause.Table{{Name: "t_1"}}},
+				fill.Fill{Type: fill.FillNullF},
+			},
+			Result: []string{"SELECT INTERP(`value`) FROM `t_1` FILL (NULL_F)"},
+			Vars:   nil,
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.Name, func(t *testing.T) {
@@ -35,3 +90,26 @@ func Test_Fill(t *testing.T) {
 		})
 	}
 }
+
+func Test_FillValuesMismatch(t *testing.T) {
+	db, _ := gorm.Open(tests.DummyDialector{}, nil)
+	stmt := &gorm.Statement{DB: db.Session(&gorm.Session{}), Clauses: map[string]clause.Clause{}}
+	stmt.AddClause(clause.Select{Columns: []clause.Column{{Name: "_wstart,avg(`value`),max(`value`)", Raw: true}}})
+	stmt.AddClause(clause.From{Tables: []clause.Table{{Name: "t_1"}}})
+	stmt.AddClause(window.SetInterval(window.Duration{Value: 10, Unit: window.Minute}))
+	stmt.AddClause(fill.SetValues(1))
+	stmt.Build("SELECT", "FROM", "WINDOW", "FILL")
+	if stmt.DB.Error == nil {
+		t.Errorf("expect values count mismatch error")
+	}
+
+	stmt = &gorm.Statement{DB: db.Session(&gorm.Session{}), Clauses: map[string]clause.Clause{}}
+	stmt.AddClause(clause.Select{Expression: clause.Expr{SQL: "?,?,?", Vars: []any{window.WStart, fn.Avg("a"), fn.Max("b")}}})
+	stmt.AddClause(clause.From{Tables: []clause.Table{{Name: "t_1"}}})
+	stmt.AddClause(window.SetInterval(window.Duration{Value: 10, Unit: window.Minute}))
+	stmt.AddClause(fill.SetValues(1))
+	stmt.Build("SELECT", "FROM", "WINDOW", "FILL")
+	if stmt.DB.Error == nil {
+		t.Errorf("expect values count mismatch error of typed functions")
+	}
+}
@@ -0,0 +1,336 @@
+package fn
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/thinkgos/tdengine-gorm/clause/window"
+	"gorm.io/gorm/clause"
+)
+
+// All the `*` argument, e.g. LAST(*)
+const All = "*"
+
+// Func TDengine function call expression, columns are quoted, parameters are validated.
+//
+//	db.Table("t_1").Select("?,?", fn.Twa("value").As("twa"), fn.Spread("value"))
+type Func struct {
+	name  string
+	args  []any
+	alias string
+	err   error
+}
+
+// rawArg argument written as it is, used by validated literal.
+type rawArg string
+
+func newFunc(name string, args ...any) Func {
+	return Func{name: name, args: args}
+}
+
+func errFunc(name string, format string, a ...any) Func {
+	return Func{name: name, err: fmt.Errorf("fn: %s "+format, append([]any{name}, a...)...)}
+}
+
+// As set alias to function
+func (f Func) As(alias string) Func {
+	f.alias = alias
+	return f
+}
+
+// FuncName name of the function, e.g. AVG
+func (f Func) FuncName() string {
+	return f.name
+}
+
+// Alias alias of the function, empty if not set
+func (f Func) Alias() string {
+	return f.alias
+}
+
+// Err validation error of the function arguments
+func (f Func) Err() error {
+	return f.err
+}
+
+// Build [name(args...) [AS alias]]
+func (f Func) Build(builder clause.Builder) {
+	if f.err != nil {
+		_ = builder.AddError(f.err)
+		return
+	}
+	_, _ = builder.WriteString(f.name)
+	_ = builder.WriteByte('(')
+	for i, arg := range f.args {
+		if i > 0 {
+			_ = builder.WriteByte(',')
+		}
+		switch v := arg.(type) {
+		case rawArg:
+			_, _ = builder.WriteString(string(v))
+		case clause.Column:
+			builder.WriteQuoted(v)
+		case clause.Expression:
+			v.Build(builder)
+		default:
+			builder.AddVar(builder, v)
+		}
+	}
+	_ = builder.WriteByte(')')
+	if f.alias != "" {
+		_, _ = builder.WriteString(" AS ")
+		builder.WriteQuoted(f.alias)
+	}
+}
+
+// column argument, `*` is written as it is.
+func column(col string) any {
+	if col == All {
+		return rawArg(All)
+	}
+	return clause.Column{Name: col}
+}
+
+func integer(v int64) rawArg {
+	return rawArg(strconv.FormatInt(v, 10))
+}
+
+func float(v float64) rawArg {
+	return rawArg(strconv.FormatFloat(v, 'g', -1, 64))
+}
+
+func duration(d window.Duration) rawArg {
+	return rawArg(d.String())
+}
+
+func validUnit(d window.Duration) error {
+	return d.Validate(false)
+}
+
+// Twa time weighted average [TWA(expr)]
+func Twa(col string) Func {
+	return newFunc("TWA", column(col))
+}
+
+// Spread difference between the max and the min value [SPREAD(expr)]
+func Spread(col string) Func {
+	return newFunc("SPREAD", column(col))
+}
+
+// Elapsed continuous time length [ELAPSED(ts_primary_key [, time_unit])]
+func Elapsed(tsCol string, unit ...window.Duration) Func {
+	args := []any{column(tsCol)}
+	if len(unit) > 0 {
+		if err := validUnit(unit[0]); err != nil {
+			return errFunc("ELAPSED", "%v", err)
+		}
+		args = append(args, duration(unit[0]))
+	}
+	return newFunc("ELAPSED", args...)
+}
+
+// ApercentileAlgo algorithm of APERCENTILE
+type ApercentileAlgo string
+
+const (
+	ApercentileDefault ApercentileAlgo = "default"
+	ApercentileTDigest ApercentileAlgo = "t-digest"
+)
+
+// Apercentile approximate percentile [APERCENTILE(expr, p [, algo_type])]
+func Apercentile(col string, p float64, algo ...ApercentileAlgo) Func {
+	if p < 0 || p > 100 {
+		return errFunc("APERCENTILE", "p must be in [0, 100], got %v", p)
+	}
+	args := []any{column(col), float(p)}
+	if len(algo) > 0 {
+		if algo[0] != ApercentileDefault && algo[0] != ApercentileTDigest {
+			return errFunc("APERCENTILE", "unknown algorithm %q", algo[0])
+		}
+		args = append(args, rawArg("'"+string(algo[0])+"'"))
+	}
+	return newFunc("APERCENTILE", args...)
+}
+
+// HistogramBin bin type of HISTOGRAM
+type HistogramBin string
+
+const (
+	HistogramUserInput HistogramBin = "user_input"
+	HistogramLinearBin HistogramBin = "linear_bin"
+	HistogramLogBin    HistogramBin = "log_bin"
+)
+
+// Histogram data distribution [HISTOGRAM(expr, bin_type, bin_description, normalized)]
+func Histogram(col string, binType HistogramBin, binDescription string, normalized bool) Func {
+	switch binType {
+	case HistogramUserInput, HistogramLinearBin, HistogramLogBin:
+	default:
+		return errFunc("HISTOGRAM", "unknown bin type %q", binType)
+	}
+	if binDescription == "" {
+		return errFunc("HISTOGRAM", "bin description required")
+	}
+	n := integer(0)
+	if normalized {
+		n = integer(1)
+	}
+	return newFunc("HISTOGRAM", column(col), rawArg("'"+string(binType)+"'"), binDescription, n)
+}
+
+// LeastSquares linear regression [LEASTSQUARES(expr, start_val, step_val)]
+func LeastSquares(col string, start, step float64) Func {
+	return newFunc("LEASTSQUARES", column(col), float(start), float(step))
+}
+
+// Csum cumulative sum [CSUM(expr)]
+func Csum(col string) Func {
+	return newFunc("CSUM", column(col))
+}
+
+// Diff difference with the previous row [DIFF(expr [, ignore_option])]
+// ignore option: 0 keep negative, 1 ignore negative, 2 keep negative and ignore null, 3 ignore negative and null.
+func Diff(col string, ignoreOption ...int) Func {
+	args := []any{column(col)}
+	if len(ignoreOption) > 0 {
+		if ignoreOption[0] < 0 || ignoreOption[0] > 3 {
+			return errFunc("DIFF", "ignore option must be in [0, 3], got %d", ignoreOption[0])
+		}
+		args = append(args, integer(int64(ignoreOption[0])))
+	}
+	return newFunc("DIFF", args...)
+}
+
+// Derivative rate of change per unit [DERIVATIVE(expr, time_interval, ignore_negative)]
+func Derivative(col string, interval window.Duration, ignoreNegative bool) Func {
+	if err := validUnit(interval); err != nil {
+		return errFunc("DERIVATIVE", "%v", err)
+	}
+	n := integer(0)
+	if ignoreNegative {
+		n = integer(1)
+	}
+	return newFunc("DERIVATIVE", column(col), duration(interval), n)
+}
+
+// Irate instantaneous rate [IRATE(expr)]
+func Irate(col string) Func {
+	return newFunc("IRATE", column(col))
+}
+
+// Mavg moving average [MAVG(expr, k)], k in [1, 1000]
+func Mavg(col string, k int) Func {
+	if k < 1 || k > 1000 {
+		return errFunc("MAVG", "k must be in [1, 1000], got %d", k)
+	}
+	return newFunc("MAVG", column(col), integer(int64(k)))
+}
+
+// Oper compare operator of STATECOUNT and STATEDURATION
+type Oper string
+
+const (
+	LT Oper = "LT"
+	GT Oper = "GT"
+	LE Oper = "LE"
+	GE Oper = "GE"
+	NE Oper = "NE"
+	EQ Oper = "EQ"
+)
+
+func validOper(oper Oper) bool {
+	switch oper {
+	case LT, GT, LE, GE, NE, EQ:
+		return true
+	}
+	return false
+}
+
+// StateCount count of continuous rows meet the condition [STATECOUNT(expr, oper, val)]
+func StateCount(col string, oper Oper, val float64) Func {
+	if !validOper(oper) {
+		return errFunc("STATECOUNT", "unknown operator %q", oper)
+	}
+	return newFunc("STATECOUNT", column(col), rawArg("'"+string(oper)+"'"), float(val))
+}
+
+// StateDuration duration of continuous rows meet the condition [STATEDURATION(expr, oper, val [, unit])]
+func StateDuration(col string, oper Oper, val float64, unit ...window.Duration) Func {
+	if !validOper(oper) {
+		return errFunc("STATEDURATION", "unknown operator %q", oper)
+	}
+	args := []any{column(col), rawArg("'" + string(oper) + "'"), float(val)}
+	if len(unit) > 0 {
+		if err := validUnit(unit[0]); err != nil {
+			return errFunc("STATEDURATION", "%v", err)
+		}
+		args = append(args, duration(unit[0]))
+	}
+	return newFunc("STATEDURATION", args...)
+}
+
+// Top the k largest values [TOP(expr, k)], k in [1, 100]
+func Top(col string, k int) Func {
+	if k < 1 || k > 100 {
+		return errFunc("TOP", "k must be in [1, 100], got %d", k)
+	}
+	return newFunc("TOP", column(col), integer(int64(k)))
+}
+
+// Bottom the k smallest values [BOTTOM(expr, k)], k in [1, 100]
+func Bottom(col string, k int) Func {
+	if k < 1 || k > 100 {
+		return errFunc("BOTTOM", "k must be in [1, 100], got %d", k)
+	}
+	return newFunc("BOTTOM", column(col), integer(int64(k)))
+}
+
+// Last the last non-null value [LAST(expr)], col can be All.
+func Last(col string) Func {
+	return newFunc("LAST", column(col))
+}
+
+// LastRow the last row [LAST_ROW(expr)], col can be All.
+func LastRow(col string) Func {
+	return newFunc("LAST_ROW", column(col))
+}
+
+// First the first non-null value [FIRST(expr)], col can be All.
+func First(col string) Func {
+	return newFunc("FIRST", column(col))
+}
+
+// Mode the most frequent value [MODE(expr)]
+func Mode(col string) Func {
+	return newFunc("MODE", column(col))
+}
+
+// Avg average value [AVG(expr)]
+func Avg(col string) Func {
+	return newFunc("AVG", column(col))
+}
+
+// Max the max value [MAX(expr)]
+func Max(col string) Func {
+	return newFunc("MAX", column(col))
+}
+
+// Min the min value [MIN(expr)]
+func Min(col string) Func {
+	return newFunc("MIN", column(col))
+}
+
+// Sum sum of the values [SUM(expr)]
+func Sum(col string) Func {
+	return newFunc("SUM", column(col))
+}
+
+// Count count of the rows [COUNT(expr)], col can be All.
+func Count(col string) Func {
+	return newFunc("COUNT", column(col))
+}
+
+// Stddev standard deviation [STDDEV(expr)]
+func Stddev(col string) Func {
+	return newFunc("STDDEV", column(col))
+}
@@ -0,0 +1,169 @@
+package fn_test
+
+import (
+	"testing"
+
+	"github.com/thinkgos/tdengine-gorm/clause/fn"
+	"github.com/thinkgos/tdengine-gorm/clause/tests"
+	"github.com/thinkgos/tdengine-gorm/clause/window"
+
+	"gorm.io/gorm/clause"
+)
+
+func selectFn(exprs ...clause.Expression) clause.Select {
+	return clause.Select{Expression: clause.CommaExpression{Exprs: exprs}}
+}
+
+func Test_Func(t *testing.T) {
+	var testCases = []struct {
+		Name    string
+		Clauses []clause.Interface
+		Result  []string
+		Vars    [][][]any
+	}{
+		{
+			Name: "aggregate",
+			Clauses: []clause.Interface{
+				selectFn(
+					fn.Twa("value").As("twa"),
+					fn.Spread("t_1.value"),
+					fn.Elapsed("ts", window.Duration{Value: 1, Unit: window.Second}),
+					fn.Apercentile("value", 90, fn.ApercentileTDigest),
+					fn.LeastSquares("value", 1, 0.5),
+					fn.Mode("value"),
+				),
+				clause.From{Tables: []clause.Table{{Name: "t_1"}}},
+			},
+			Result: []string{"SELECT TWA(`value`) AS `twa`, SPREAD(`t_1`.`value`), ELAPSED(`ts`,1s), APERCENTILE(`value`,90,'t-digest'), LEASTSQUARES(`value`,1,0.5), MODE(`value`) FROM `t_1`"},
+			Vars:   nil,
+		},
+		{
+			Name: "basic aggregate",
+			Clauses: []clause.Interface{
+				selectFn(fn.Avg("value"), fn.Max("value"), fn.Min("value"), fn.Sum("value"), fn.Count(fn.All), fn.Stddev("value")),
+				clause.From{Tables: []clause.Table{{Name: "t_1"}}},
+			},
+			Result: []string{"SELECT AVG(`value`), MAX(`value`), MIN(`value`), SUM(`value`), COUNT(*), STDDEV(`value`) FROM `t_1`"},
+			Vars:   nil,
+		},
+		{
+			Name: "histogram",
+			Clauses: []clause.Interface{
+				selectFn(fn.Histogram("value", fn.HistogramUserInput, "[1,3,5,7]", true)),
+				clause.From{Tables: []clause.Table{{Name: "t_1"}}},
+			},
+			Result: []string{"SELECT HISTOGRAM(`value`,'user_input',?,1) FROM `t_1`"},
+			Vars:   [][][]any{{{"[1,3,5,7]"}}},
+		},
+		{
+			Name: "time series",
+			Clauses: []clause.Interface{
+				selectFn(
+					fn.Csum("value"),
+					fn.Diff("value", 1),
+					fn.Derivative("value", window.Duration{Value: 1, Unit: window.Second}, true),
+					fn.Irate("value"),
+					fn.Mavg("value", 3).As("mavg"),
+				),
+				clause.From{Tables: []clause.Table{{Name: "t_1"}}},
+			},
+			Result: []string{"SELECT CSUM(`value`), DIFF(`value`,1), DERIVATIVE(`value`,1s,1), IRATE(`value`), MAVG(`value`,3) AS `mavg` FROM `t_1`"},
+			Vars:   nil,
+		},
+		{
+			Name: "state",
+			Clauses: []clause.Interface{
+				selectFn(
+					fn.StateCount("value", fn.GT, 80),
+					fn.StateDuration("value", fn.LE, 75.5, window.Duration{Value: 1, Unit: window.Minute}),
+				),
+				clause.From{Tables: []clause.Table{{Name: "t_1"}}},
+			},
+			Result: []string{"SELECT STATECOUNT(`value`,'GT',80), STATEDURATION(`value`,'LE',75.5,1m) FROM `t_1`"},
+			Vars:   nil,
+		},
+		{
+			Name: "selector",
+			Clauses: []clause.Interface{
+				selectFn(
+					fn.Top("value", 3),
+					fn.Bottom("value", 3),
+					fn.First("value"),
+					fn.Last(fn.All),
+					fn.LastRow(fn.All),
+				),
+				clause.From{Tables: []clause.Table{{Name: "t_1"}}},
+			},
+			Result: []string{"SELECT TOP(`value`,3), BOTTOM(`value`,3), FIRST(`value`), LAST(*), LAST_ROW(*) FROM `t_1`"},
+			Vars:   nil,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tests.CheckBuildClauses(t, tc.Clauses, tc.Result, tc.Vars)
+		})
+	}
+}
+
+func Test_TimeFunc(t *testing.T) {
+	var testCases = []struct {
+		Name    string
+		Clauses []clause.Interface
+		Result  []string
+		Vars    [][][]any
+	}{
+		{
+			Name: "",
+			Clauses: []clause.Interface{
+				selectFn(
+					fn.TimeTruncate("ts", window.Duration{Value: 1, Unit: window.Hour}, true).As("hour"),
+					fn.TimeDiff(fn.Now(), "ts", window.Duration{Value: 1, Unit: window.Second}),
+					fn.ToISO8601(fn.Today().Sub(window.Duration{Value: 1, Unit: window.Day}), "+08:00"),
+				),
+				clause.From{Tables: []clause.Table{{Name: "t_1"}}},
+			},
+			Result: []string{"SELECT TIMETRUNCATE(`ts`,1h,1) AS `hour`, TIMEDIFF(NOW,`ts`,1s), TO_ISO8601(TODAY() - 1d,?) FROM `t_1`"},
+			Vars:   [][][]any{{{"+08:00"}}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tests.CheckBuildClauses(t, tc.Clauses, tc.Result, tc.Vars)
+		})
+	}
+}
+
+func Test_FuncValidate(t *testing.T) {
+	testCases := []struct {
+		name string
+		f    fn.Func
+	}{
+		{"elapsed month", fn.Elapsed("ts", window.Duration{Value: 1, Unit: window.Month})},
+		{"apercentile p", fn.Apercentile("value", 101)},
+		{"apercentile algo", fn.Apercentile("value", 50, "unknown")},
+		{"histogram bin type", fn.Histogram("value", "unknown", "[1]", false)},
+		{"diff ignore option", fn.Diff("value", 4)},
+		{"derivative zero", fn.Derivative("value", window.Duration{Unit: window.Second}, false)},
+		{"mavg k", fn.Mavg("value", 0)},
+		{"statecount oper", fn.StateCount("value", "XX", 1)},
+		{"top k", fn.Top("value", 101)},
+		{"bottom k", fn.Bottom("value", 0)},
+		{"timetruncate month", fn.TimeTruncate("ts", window.Duration{Value: 1, Unit: window.Month})},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.f.Err() == nil {
+				t.Errorf("expect validation error")
+			}
+		})
+	}
+}
+
+func Test_TimeExprValidate(t *testing.T) {
+	if err := fn.Now().Sub(window.Duration{Value: 1, Unit: window.Year}).Err(); err == nil {
+		t.Errorf("expect validation error")
+	}
+	if err := fn.Now().Sub(window.Duration{Value: 1, Unit: window.Hour}).Err(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
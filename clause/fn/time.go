@@ -0,0 +1,114 @@
+package fn
+
+import (
+	"github.com/thinkgos/tdengine-gorm/clause/window"
+	"gorm.io/gorm/clause"
+)
+
+// TimeExpr server side time expression, evaluated on the server clock, e.g. [NOW - 15m]
+type TimeExpr struct {
+	base string
+	ops  []timeOp
+	err  error
+}
+
+type timeOp struct {
+	sign     byte
+	duration window.Duration
+}
+
+// Now current time of the server [NOW]
+func Now() TimeExpr {
+	return TimeExpr{base: "NOW"}
+}
+
+// Today zero o'clock of today on the server [TODAY()]
+func Today() TimeExpr {
+	return TimeExpr{base: "TODAY()"}
+}
+
+// Add time expression + duration, month and year unit are not allowed.
+func (t TimeExpr) Add(d window.Duration) TimeExpr {
+	return t.op('+', d)
+}
+
+// Sub time expression - duration, month and year unit are not allowed.
+func (t TimeExpr) Sub(d window.Duration) TimeExpr {
+	return t.op('-', d)
+}
+
+func (t TimeExpr) op(sign byte, d window.Duration) TimeExpr {
+	if err := validUnit(d); err != nil && t.err == nil {
+		t.err = err
+	}
+	ops := make([]timeOp, 0, len(t.ops)+1)
+	ops = append(ops, t.ops...)
+	t.ops = append(ops, timeOp{sign: sign, duration: d})
+	return t
+}
+
+// Err validation error of the time expression
+func (t TimeExpr) Err() error {
+	return t.err
+}
+
+// Build [NOW|TODAY() [+|- duration] ...]
+func (t TimeExpr) Build(builder clause.Builder) {
+	if t.err != nil {
+		_ = builder.AddError(t.err)
+		return
+	}
+	_, _ = builder.WriteString(t.base)
+	for _, op := range t.ops {
+		_ = builder.WriteByte(' ')
+		_ = builder.WriteByte(op.sign)
+		_ = builder.WriteByte(' ')
+		_, _ = builder.WriteString(string(duration(op.duration)))
+	}
+}
+
+// timeArg argument of the time functions,
+// string is a column, time.Time is bound as parameter, clause.Expression is written as it is.
+func timeArg(v any) any {
+	if col, ok := v.(string); ok {
+		return column(col)
+	}
+	return v
+}
+
+// TimeTruncate truncate the time to the unit [TIMETRUNCATE(expr, time_unit [, use_current_timezone])]
+func TimeTruncate(expr any, unit window.Duration, useCurrentTimezone ...bool) Func {
+	if err := validUnit(unit); err != nil {
+		return errFunc("TIMETRUNCATE", "%v", err)
+	}
+	args := []any{timeArg(expr), duration(unit)}
+	if len(useCurrentTimezone) > 0 {
+		if useCurrentTimezone[0] {
+			args = append(args, integer(1))
+		} else {
+			args = append(args, integer(0))
+		}
+	}
+	return newFunc("TIMETRUNCATE", args...)
+}
+
+// TimeDiff difference of the two time [TIMEDIFF(expr1, expr2 [, time_unit])]
+func TimeDiff(expr1, expr2 any, unit ...window.Duration) Func {
+	args := []any{timeArg(expr1), timeArg(expr2)}
+	if len(unit) > 0 {
+		if err := validUnit(unit[0]); err != nil {
+			return errFunc("TIMEDIFF", "%v", err)
+		}
+		args = append(args, duration(unit[0]))
+	}
+	return newFunc("TIMEDIFF", args...)
+}
+
+// ToISO8601 convert the time to ISO8601 format [TO_ISO8601(expr [, timezone])]
+func ToISO8601(expr any, timezone ...string) Func {
+	args := []any{timeArg(expr)}
+	if len(timezone) > 0 {
+		args = append(args, timezone[0])
+	}
+	return newFunc("TO_ISO8601", args...)
+}
@@ -0,0 +1,111 @@
+package hint
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Hint optimizer hint of the query
+type Hint string
+
+const (
+	// BatchScan read the tables in batch, used by join queries.
+	BatchScan Hint = "BATCH_SCAN"
+	// NoBatchScan read the tables in sequence, used by join queries.
+	NoBatchScan Hint = "NO_BATCH_SCAN"
+	// SortForGroup sort the data for PARTITION BY instead of hashing.
+	SortForGroup Hint = "SORT_FOR_GROUP"
+	// ParaTablesSort sort the data of the super table in parallel tables.
+	ParaTablesSort Hint = "PARA_TABLES_SORT"
+	// PartitionFirst partition before aggregating, used with PARTITION BY.
+	PartitionFirst Hint = "PARTITION_FIRST"
+	// SmallDataTsSort sort by timestamp for small data in the super table.
+	SmallDataTsSort Hint = "SMALLDATA_TS_SORT"
+)
+
+var hintMap = map[Hint]struct{}{
+	BatchScan:       {},
+	NoBatchScan:     {},
+	SortForGroup:    {},
+	ParaTablesSort:  {},
+	PartitionFirst:  {},
+	SmallDataTsSort: {},
+}
+
+// requirePartition hints only valid with PARTITION BY
+var requirePartition = map[Hint]struct{}{
+	SortForGroup:   {},
+	PartitionFirst: {},
+}
+
+// Hints hints rendered right after SELECT [SELECT /*+ hint() [hint() ...] */ ...]
+//
+//	db.Clauses(hint.New(hint.ParaTablesSort)).Find(&rows)
+type Hints struct {
+	hints []Hint
+}
+
+// New create hints
+func New(hints ...Hint) Hints {
+	return Hints{hints: hints}
+}
+
+// Hints the hints
+func (h Hints) Hints() []Hint {
+	return h.hints
+}
+
+// ModifyStatement put the hints after the SELECT keyword.
+func (h Hints) ModifyStatement(stmt *gorm.Statement) {
+	c := stmt.Clauses["SELECT"]
+	if v, ok := c.AfterNameExpression.(Hints); ok {
+		hints := make([]Hint, 0, len(v.hints)+len(h.hints))
+		hints = append(hints, v.hints...)
+		h.hints = append(hints, h.hints...)
+	}
+	c.AfterNameExpression = h
+	stmt.Clauses["SELECT"] = c
+}
+
+// Build [/*+ hint() ... */]
+func (h Hints) Build(builder clause.Builder) {
+	if err := h.validate(builder); err != nil {
+		_ = builder.AddError(err)
+		return
+	}
+	_, _ = builder.WriteString("/*+")
+	for _, v := range h.hints {
+		_ = builder.WriteByte(' ')
+		_, _ = builder.WriteString(string(v))
+		_, _ = builder.WriteString("()")
+	}
+	_, _ = builder.WriteString(" */")
+}
+
+func (h Hints) validate(builder clause.Builder) error {
+	seen := make(map[Hint]struct{}, len(h.hints))
+	for _, v := range h.hints {
+		if _, ok := hintMap[v]; !ok {
+			return fmt.Errorf("hint: unknown hint %q", v)
+		}
+		if _, ok := seen[v]; ok {
+			return fmt.Errorf("hint: duplicate hint %q", v)
+		}
+		seen[v] = struct{}{}
+		if stmt, ok := builder.(*gorm.Statement); ok {
+			if _, ok := requirePartition[v]; ok {
+				if _, ok := stmt.Clauses["PARTITION BY"]; !ok {
+					return fmt.Errorf("hint: %s requires PARTITION BY", v)
+				}
+			}
+		}
+	}
+	_, batch := seen[BatchScan]
+	_, noBatch := seen[NoBatchScan]
+	if batch && noBatch {
+		return fmt.Errorf("hint: %s conflicts with %s", BatchScan, NoBatchScan)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package hint_test
+
+import (
+	"testing"
+
+	"github.com/thinkgos/tdengine-gorm/clause/hint"
+	"github.com/thinkgos/tdengine-gorm/clause/partition"
+	"github.com/thinkgos/tdengine-gorm/clause/tests"
+	"github.com/thinkgos/tdengine-gorm/clause/window"
+
+	"gorm.io/gorm"
+)
+
+func Test_Hints(t *testing.T) {
+	var testCases = []struct {
+		Name   string
+		Query  func(tx *gorm.DB) *gorm.DB
+		Result string
+	}{
+		{
+			Name: "para tables sort",
+			Query: func(tx *gorm.DB) *gorm.DB {
+				return tx.Table("meters").Clauses(hint.New(hint.ParaTablesSort)).Order("`ts`").Find(&[]map[string]any{})
+			},
+			Result: "SELECT /*+ PARA_TABLES_SORT() */ * FROM `meters` ORDER BY `ts`",
+		},
+		{
+			Name: "partition first",
+			Query: func(tx *gorm.DB) *gorm.DB {
+				return tx.Table("meters").
+					Select("avg(`current`)").
+					Clauses(
+						hint.New(hint.PartitionFirst),
+						hint.New(hint.BatchScan),
+						partition.PartitionBy{}.TbName(),
+						window.SetInterval(window.Duration{Value: 1, Unit: window.Minute}),
+					).
+					Find(&[]map[string]any{})
+			},
+			Result: "SELECT /*+ PARTITION_FIRST() BATCH_SCAN() */ avg(`current`) FROM `meters` PARTITION BY tbname INTERVAL(1m)",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tests.CheckBuildQuery(t, tc.Query, tc.Result, nil)
+		})
+	}
+}
+
+func Test_HintsInvalid(t *testing.T) {
+	db, _ := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	var testCases = []struct {
+		Name  string
+		Hints hint.Hints
+	}{
+		{"unknown", hint.New("NO_SUCH_HINT")},
+		{"duplicate", hint.New(hint.ParaTablesSort, hint.ParaTablesSort)},
+		{"conflict", hint.New(hint.BatchScan, hint.NoBatchScan)},
+		{"misplaced", hint.New(hint.SortForGroup)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := db.Table("meters").Clauses(tc.Hints).Find(&[]map[string]any{}).Error
+			if err == nil {
+				t.Errorf("expect error")
+			}
+		})
+	}
+}
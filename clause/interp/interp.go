@@ -0,0 +1,67 @@
+package interp
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/thinkgos/tdengine-gorm/clause/window"
+	"gorm.io/gorm/clause"
+)
+
+// Range range clause used by INTERP [RANGE(start_ts_val, end_ts_val)]
+type Range struct {
+	Start time.Time
+	End   time.Time // if zero, only interpolate at Start [RANGE(ts_val)]
+}
+
+func (r Range) Name() string {
+	return "RANGE"
+}
+
+// Build RANGE clause
+func (r Range) Build(builder clause.Builder) {
+	if r.Start.IsZero() {
+		_ = builder.AddError(errors.New("range: start required"))
+		return
+	}
+	if !r.End.IsZero() && r.End.Before(r.Start) {
+		_ = builder.AddError(fmt.Errorf("range: end %s before start %s", r.End.Format(time.RFC3339Nano), r.Start.Format(time.RFC3339Nano)))
+		return
+	}
+	_ = builder.WriteByte('(')
+	builder.AddVar(builder, r.Start)
+	if !r.End.IsZero() {
+		_ = builder.WriteByte(',')
+		builder.AddVar(builder, r.End)
+	}
+	_ = builder.WriteByte(')')
+}
+
+func (r Range) MergeClause(c *clause.Clause) {
+	c.Expression = r
+}
+
+// Every every clause used by INTERP [EVERY(every_val)]
+type Every struct {
+	Duration window.Duration
+}
+
+func (e Every) Name() string {
+	return "EVERY"
+}
+
+// Build EVERY clause
+func (e Every) Build(builder clause.Builder) {
+	if err := e.Duration.Validate(false); err != nil {
+		_ = builder.AddError(fmt.Errorf("every: %w", err))
+		return
+	}
+	_ = builder.WriteByte('(')
+	_, _ = builder.WriteString(e.Duration.String())
+	_ = builder.WriteByte(')')
+}
+
+func (e Every) MergeClause(c *clause.Clause) {
+	c.Expression = e
+}
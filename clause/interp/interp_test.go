@@ -0,0 +1,75 @@
+package interp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thinkgos/tdengine-gorm/clause/fill"
+	"github.com/thinkgos/tdengine-gorm/clause/interp"
+	"github.com/thinkgos/tdengine-gorm/clause/partition"
+	"github.com/thinkgos/tdengine-gorm/clause/tests"
+	"github.com/thinkgos/tdengine-gorm/clause/window"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func Test_Interp(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	var testCases = []struct {
+		Name    string
+		Clauses []clause.Interface
+		Result  []string
+		Vars    [][][]any
+	}{
+		{
+			Name: "range every fill",
+			Clauses: []clause.Interface{
+				clause.Select{Columns: []clause.Column{{Name: "INTERP(`t_1`.`value`)", Raw: true}}},
+				clause.From{Tables: []clause.Table{{Name: "t_1"}}},
+				interp.Range{Start: start, End: end},
+				interp.Every{Duration: window.Duration{Value: 1, Unit: window.Second}},
+				fill.Fill{Type: fill.FillLinear},
+			},
+			Result: []string{"SELECT INTERP(`t_1`.`value`) FROM `t_1` RANGE (?,?) EVERY (1s) FILL (LINEAR)"},
+			Vars:   [][][]any{{{start, end}}},
+		},
+		{
+			Name: "single point with partition",
+			Clauses: []clause.Interface{
+				clause.Select{Columns: []clause.Column{{Name: "INTERP(`value`)", Raw: true}}},
+				clause.From{Tables: []clause.Table{{Name: "st_1"}}},
+				partition.PartitionBy{}.TbName(),
+				interp.Range{Start: start},
+				fill.Fill{Type: fill.FillPrev},
+			},
+			Result: []string{"SELECT INTERP(`value`) FROM `st_1` PARTITION BY tbname RANGE (?) FILL (PREV)"},
+			Vars:   [][][]any{{{start}}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tests.CheckBuildClauses(t, tc.Clauses, tc.Result, tc.Vars)
+		})
+	}
+}
+
+func Test_RangeInvalid(t *testing.T) {
+	db, _ := gorm.Open(tests.DummyDialector{}, nil)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for name, r := range map[string]interp.Range{
+		"zero start":       {End: start},
+		"end before start": {Start: start, End: start.Add(-time.Second)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			stmt := &gorm.Statement{DB: db.Session(&gorm.Session{}), Clauses: map[string]clause.Clause{}}
+			stmt.AddClause(r)
+			stmt.Build(r.Name())
+			if stmt.DB.Error == nil {
+				t.Errorf("expect error")
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package join
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/thinkgos/tdengine-gorm/clause/window"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	ASOF = iota + 1
+	WINDOW
+)
+
+// Side outer side of the time-series join
+type Side string
+
+const (
+	Left  Side = "LEFT"
+	Right Side = "RIGHT"
+)
+
+// TsOperator operator of the primary timestamp condition of ASOF JOIN
+type TsOperator string
+
+const (
+	TsGte TsOperator = ">="
+	TsGt  TsOperator = ">"
+	TsEq  TsOperator = "="
+	TsLt  TsOperator = "<"
+	TsLte TsOperator = "<="
+)
+
+// Offset signed offset of WINDOW_OFFSET
+type Offset struct {
+	Duration window.Duration
+	Negative bool
+}
+
+// Before negative offset, the window starts before the row of the left table.
+func Before(d window.Duration) Offset {
+	return Offset{Duration: d, Negative: true}
+}
+
+// After positive offset, the window ends after the row of the left table.
+func After(d window.Duration) Offset {
+	return Offset{Duration: d}
+}
+
+// signed duration of the offset, zero offset is allowed, month and year are not.
+func (o Offset) signed() (time.Duration, error) {
+	if o.Duration.Value == 0 {
+		return 0, nil
+	}
+	if err := o.Duration.Validate(false); err != nil {
+		return 0, err
+	}
+	d, err := o.Duration.ToDuration()
+	if err != nil {
+		return 0, err
+	}
+	if o.Negative {
+		d = -d
+	}
+	return d, nil
+}
+
+// [LEFT|RIGHT ASOF JOIN table [ON left_ts op right_ts [AND cond ...]] [JLIMIT n]]
+// [LEFT|RIGHT WINDOW JOIN table [ON cond [AND cond ...]] WINDOW_OFFSET(start, end) [JLIMIT n]]
+
+// Join time-series join
+type Join struct {
+	joinType int
+	side     Side
+	table    clause.Table
+	leftTs   string
+	tsOp     TsOperator
+	rightTs  string
+	on       []clause.Expression
+	start    Offset
+	end      Offset
+	jlimit   int
+}
+
+// Joins time-series joins clause
+type Joins struct {
+	Joins []Join
+}
+
+// SetAsofJoin create an asof join [LEFT|RIGHT ASOF JOIN table]
+func SetAsofJoin(side Side, table clause.Table) Join {
+	return Join{joinType: ASOF, side: side, table: table, jlimit: -1}
+}
+
+// SetWindowJoin create a window join [LEFT|RIGHT WINDOW JOIN table WINDOW_OFFSET(start, end)]
+func SetWindowJoin(side Side, table clause.Table, start, end Offset) Join {
+	return Join{joinType: WINDOW, side: side, table: table, start: start, end: end, jlimit: -1}
+}
+
+// OnTs set the primary timestamp condition to asof join [ON left_ts op right_ts]
+func (j Join) OnTs(leftTs string, op TsOperator, rightTs string) Join {
+	if j.joinType == ASOF {
+		j.leftTs, j.tsOp, j.rightTs = leftTs, op, rightTs
+	}
+	return j
+}
+
+// On add extra conditions to join, e.g. tag equality
+func (j Join) On(exprs ...clause.Expression) Join {
+	on := make([]clause.Expression, 0, len(j.on)+len(exprs))
+	on = append(on, j.on...)
+	j.on = append(on, exprs...)
+	return j
+}
+
+// JLimit set the max number of matched rows of the right table [JLIMIT n], n in [0, 1024], -1 unset
+func (j Join) JLimit(n int) Join {
+	j.jlimit = n
+	return j
+}
+
+func (j Join) Build(builder clause.Builder) {
+	if j.side != Left && j.side != Right {
+		_ = builder.AddError(errors.New("time-series join side must be LEFT or RIGHT"))
+		return
+	}
+	if j.jlimit < -1 || j.jlimit > 1024 {
+		_ = builder.AddError(errors.New("JLIMIT must be in [0, 1024]"))
+		return
+	}
+	_, _ = builder.WriteString(string(j.side))
+	switch j.joinType {
+	case ASOF:
+		_, _ = builder.WriteString(" ASOF JOIN ")
+	case WINDOW:
+		_, _ = builder.WriteString(" WINDOW JOIN ")
+	default:
+		_ = builder.AddError(errors.New("unsupported time-series join type"))
+		return
+	}
+	builder.WriteQuoted(j.table)
+
+	conds := make([]clause.Expression, 0, len(j.on)+1)
+	if j.tsOp != "" {
+		switch j.tsOp {
+		case TsGte, TsGt, TsEq, TsLt, TsLte:
+		default:
+			_ = builder.AddError(errors.New("unsupported primary timestamp operator " + string(j.tsOp)))
+			return
+		}
+		conds = append(conds, clause.Expr{
+			SQL:  "? " + string(j.tsOp) + " ?",
+			Vars: []any{clause.Column{Name: j.leftTs}, clause.Column{Name: j.rightTs}},
+		})
+	}
+	conds = append(conds, j.on...)
+	if len(conds) > 0 {
+		_, _ = builder.WriteString(" ON ")
+		for i, cond := range conds {
+			if i > 0 {
+				_, _ = builder.WriteString(" AND ")
+			}
+			cond.Build(builder)
+		}
+	}
+	if j.joinType == WINDOW {
+		start, err := j.start.signed()
+		if err != nil {
+			_ = builder.AddError(fmt.Errorf("WINDOW_OFFSET start: %w", err))
+			return
+		}
+		end, err := j.end.signed()
+		if err != nil {
+			_ = builder.AddError(fmt.Errorf("WINDOW_OFFSET end: %w", err))
+			return
+		}
+		if start > end {
+			_ = builder.AddError(errors.New("WINDOW_OFFSET start must not be after end"))
+			return
+		}
+		_, _ = builder.WriteString(" WINDOW_OFFSET(")
+		writeOffset(builder, j.start)
+		_ = builder.WriteByte(',')
+		writeOffset(builder, j.end)
+		_ = builder.WriteByte(')')
+	}
+	if j.jlimit >= 0 {
+		_, _ = builder.WriteString(" JLIMIT ")
+		_, _ = builder.WriteString(strconv.Itoa(j.jlimit))
+	}
+}
+
+func writeOffset(builder clause.Builder, offset Offset) {
+	if offset.Duration.Value == 0 {
+		_, _ = builder.WriteString("0s")
+		return
+	}
+	if offset.Negative {
+		_ = builder.WriteByte('-')
+	}
+	_, _ = builder.WriteString(offset.Duration.String())
+}
+
+func (j Join) Name() string {
+	return "JOIN"
+}
+
+func (j Join) MergeClause(c *clause.Clause) {
+	Joins{Joins: []Join{j}}.MergeClause(c)
+}
+
+func (js Joins) Build(builder clause.Builder) {
+	for i, j := range js.Joins {
+		if i > 0 {
+			_ = builder.WriteByte(' ')
+		}
+		j.Build(builder)
+	}
+}
+
+func (js Joins) Name() string {
+	return "JOIN"
+}
+
+// MergeClause merge JOIN by clauses
+func (js Joins) MergeClause(c *clause.Clause) {
+	c.Name = ""
+	if v, ok := c.Expression.(Joins); ok {
+		joins := make([]Join, 0, len(v.Joins)+len(js.Joins))
+		joins = append(joins, v.Joins...)
+		js.Joins = append(joins, js.Joins...)
+	}
+	c.Expression = js
+}
@@ -0,0 +1,102 @@
+package join_test
+
+import (
+	"testing"
+
+	"github.com/thinkgos/tdengine-gorm/clause/join"
+	"github.com/thinkgos/tdengine-gorm/clause/tests"
+	"github.com/thinkgos/tdengine-gorm/clause/window"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func Test_Join(t *testing.T) {
+	second := window.Duration{Value: 1, Unit: window.Second}
+	var testCases = []struct {
+		Name    string
+		Clauses []clause.Interface
+		Result  []string
+		Vars    [][][]any
+	}{
+		{
+			Name: "asof join",
+			Clauses: []clause.Interface{
+				clause.Select{Columns: []clause.Column{{Name: "a.ts"}, {Name: "a.current"}, {Name: "b.temperature"}}},
+				clause.From{Tables: []clause.Table{{Name: "d1001", Alias: "a"}}},
+				join.SetAsofJoin(join.Left, clause.Table{Name: "s1001", Alias: "b"}).
+					OnTs("a.ts", join.TsGte, "b.ts").
+					On(clause.Eq{Column: clause.Column{Table: "a", Name: "location"}, Value: clause.Column{Table: "b", Name: "location"}}).
+					JLimit(1),
+			},
+			Result: []string{"SELECT `a`.`ts`,`a`.`current`,`b`.`temperature` FROM `d1001` `a` LEFT ASOF JOIN `s1001` `b` ON `a`.`ts` >= `b`.`ts` AND `a`.`location` = `b`.`location` JLIMIT 1"},
+			Vars:   nil,
+		},
+		{
+			Name: "window join",
+			Clauses: []clause.Interface{
+				clause.Select{Columns: []clause.Column{{Name: "a.ts"}, {Name: "avg(b.temperature)", Raw: true}}},
+				clause.From{Tables: []clause.Table{{Name: "d1001", Alias: "a"}}},
+				join.SetWindowJoin(join.Right, clause.Table{Name: "s1001", Alias: "b"}, join.Before(second), join.After(second)).
+					On(clause.Expr{SQL: "a.location = ?", Vars: []any{"bj"}}).
+					JLimit(10),
+				clause.Where{Exprs: []clause.Expression{clause.Gt{Column: clause.Column{Table: "a", Name: "current"}, Value: 10}}},
+			},
+			Result: []string{"SELECT `a`.`ts`,avg(b.temperature) FROM `d1001` `a` RIGHT WINDOW JOIN `s1001` `b` ON a.location = ? WINDOW_OFFSET(-1s,1s) JLIMIT 10 WHERE `a`.`current` > ?"},
+			Vars:   [][][]any{{{"bj", 10}}},
+		},
+		{
+			Name: "zero offset",
+			Clauses: []clause.Interface{
+				clause.Select{Columns: []clause.Column{{Name: "a.ts"}}},
+				clause.From{Tables: []clause.Table{{Name: "d1001", Alias: "a"}}},
+				join.SetWindowJoin(join.Left, clause.Table{Name: "s1001", Alias: "b"}, join.Offset{}, join.After(second)),
+			},
+			Result: []string{"SELECT `a`.`ts` FROM `d1001` `a` LEFT WINDOW JOIN `s1001` `b` WINDOW_OFFSET(0s,1s)"},
+			Vars:   nil,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tests.CheckBuildClauses(t, tc.Clauses, tc.Result, tc.Vars)
+		})
+	}
+}
+
+func Test_JoinQuery(t *testing.T) {
+	second := window.Duration{Value: 1, Unit: window.Second}
+	tests.CheckBuildQuery(t, func(tx *gorm.DB) *gorm.DB {
+		return tx.Table("d1001 AS a").
+			Select("a.ts, a.current, b.temperature").
+			Clauses(
+				join.SetAsofJoin(join.Left, clause.Table{Name: "s1001", Alias: "b"}).OnTs("a.ts", join.TsLte, "b.ts"),
+				join.SetWindowJoin(join.Left, clause.Table{Name: "s1002", Alias: "c"}, join.Before(second), join.After(second)),
+			).
+			Where("a.current > ?", 10).
+			Find(&[]map[string]any{})
+	},
+		"SELECT a.ts, a.current, b.temperature FROM d1001 AS a LEFT ASOF JOIN `s1001` `b` ON `a`.`ts` <= `b`.`ts` LEFT WINDOW JOIN `s1002` `c` WINDOW_OFFSET(-1s,1s) WHERE a.current > ?",
+		[]any{10},
+	)
+}
+
+func Test_JoinInvalid(t *testing.T) {
+	db, _ := gorm.Open(tests.DummyDialector{}, nil)
+	second := window.Duration{Value: 1, Unit: window.Second}
+	table := clause.Table{Name: "s1001", Alias: "b"}
+	for name, c := range map[string]join.Join{
+		"negative jlimit": join.SetAsofJoin(join.Left, table).JLimit(-2),
+		"calendar offset": join.SetWindowJoin(join.Left, table, join.Before(window.Duration{Value: 1, Unit: window.Month}), join.After(second)),
+		"invalid unit":    join.SetWindowJoin(join.Left, table, join.Before(window.Duration{Value: 1, Unit: "x"}), join.After(second)),
+		"start after end": join.SetWindowJoin(join.Left, table, join.After(window.Duration{Value: 2, Unit: window.Second}), join.After(second)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			stmt := &gorm.Statement{DB: db.Session(&gorm.Session{}), Clauses: map[string]clause.Clause{}}
+			stmt.AddClause(c)
+			stmt.Build(c.Name())
+			if stmt.DB.Error == nil {
+				t.Errorf("expect error")
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package partition
+
+import (
+	"errors"
+
+	"gorm.io/gorm/clause"
+)
+
+// TbName the table name pseudo column of the child table.
+const TbName = "tbname"
+
+// PartitionBy partition by clause [PARTITION BY part_list]
+type PartitionBy struct {
+	parts []clause.Expression
+}
+
+// SetPartitionBy create a partition by clause with columns
+func SetPartitionBy(columns ...string) PartitionBy {
+	return PartitionBy{}.Columns(columns...)
+}
+
+// Columns add columns to partition by clause
+func (p PartitionBy) Columns(columns ...string) PartitionBy {
+	parts := make([]clause.Expression, 0, len(p.parts)+len(columns))
+	parts = append(parts, p.parts...)
+	for _, column := range columns {
+		parts = append(parts, clause.Expr{SQL: "?", Vars: []any{clause.Column{Name: column}}})
+	}
+	p.parts = parts
+	return p
+}
+
+// TbName add the tbname pseudo column to partition by clause
+func (p PartitionBy) TbName() PartitionBy {
+	return p.Expr(TbName)
+}
+
+// Tags add tag columns to partition by clause
+func (p PartitionBy) Tags(tags ...string) PartitionBy {
+	return p.Columns(tags...)
+}
+
+// Expr add an expression to partition by clause
+func (p PartitionBy) Expr(sql string, vars ...any) PartitionBy {
+	return p.Expressions(clause.Expr{SQL: sql, Vars: vars})
+}
+
+// Expressions add expressions to partition by clause
+func (p PartitionBy) Expressions(exprs ...clause.Expression) PartitionBy {
+	parts := make([]clause.Expression, 0, len(p.parts)+len(exprs))
+	parts = append(parts, p.parts...)
+	p.parts = append(parts, exprs...)
+	return p
+}
+
+func (p PartitionBy) Name() string {
+	return "PARTITION BY"
+}
+
+// Build PARTITION BY clause
+func (p PartitionBy) Build(builder clause.Builder) {
+	if len(p.parts) == 0 {
+		_ = builder.AddError(errors.New("partition by: columns or expressions required"))
+		return
+	}
+	for i, part := range p.parts {
+		if i > 0 {
+			_ = builder.WriteByte(',')
+		}
+		part.Build(builder)
+	}
+}
+
+// MergeClause merge PARTITION BY by clauses
+func (p PartitionBy) MergeClause(c *clause.Clause) {
+	if v, ok := c.Expression.(PartitionBy); ok {
+		p = v.Expressions(p.parts...)
+	}
+	c.Expression = p
+}
@@ -0,0 +1,77 @@
+package partition_test
+
+import (
+	"testing"
+
+	"github.com/thinkgos/tdengine-gorm/clause/fill"
+	"github.com/thinkgos/tdengine-gorm/clause/partition"
+	"github.com/thinkgos/tdengine-gorm/clause/slimit"
+	"github.com/thinkgos/tdengine-gorm/clause/tests"
+	"github.com/thinkgos/tdengine-gorm/clause/window"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func Test_PartitionBy(t *testing.T) {
+	var testCases = []struct {
+		Name    string
+		Clauses []clause.Interface
+		Result  []string
+		Vars    [][][]any
+	}{
+		{
+			Name: "tbname",
+			Clauses: []clause.Interface{
+				clause.Select{Columns: []clause.Column{{Name: "avg(`value`)", Raw: true}}},
+				clause.From{Tables: []clause.Table{{Name: "st_1"}}},
+				partition.PartitionBy{}.TbName(),
+				window.SetInterval(window.Duration{Value: 10, Unit: window.Minute}),
+				fill.Fill{Type: fill.FillNull},
+				slimit.SLimit{Limit: 2},
+			},
+			Result: []string{"SELECT avg(`value`) FROM `st_1` PARTITION BY tbname INTERVAL(10m) FILL (NULL) SLIMIT 2"},
+			Vars:   nil,
+		},
+		{
+			Name: "columns tags and expression",
+			Clauses: []clause.Interface{
+				clause.Select{Columns: []clause.Column{{Name: "avg(`value`)", Raw: true}}},
+				clause.From{Tables: []clause.Table{{Name: "st_1"}}},
+				clause.Where{Exprs: []clause.Expression{clause.Eq{Column: clause.Column{Name: "location"}, Value: "bj"}}},
+				partition.SetPartitionBy("group_id").
+					Tags("location").
+					Expr("`value` > ?", 10),
+				window.SetInterval(window.Duration{Value: 1, Unit: window.Hour}),
+			},
+			Result: []string{"SELECT avg(`value`) FROM `st_1` WHERE `location` = ? PARTITION BY `group_id`,`location`,`value` > ? INTERVAL(1h)"},
+			Vars:   [][][]any{{{"bj", 10}}},
+		},
+		{
+			Name: "merge",
+			Clauses: []clause.Interface{
+				clause.Select{Columns: []clause.Column{{Name: "count(*)", Raw: true}}},
+				clause.From{Tables: []clause.Table{{Name: "st_1"}}},
+				partition.PartitionBy{}.TbName(),
+				partition.SetPartitionBy("location"),
+			},
+			Result: []string{"SELECT count(*) FROM `st_1` PARTITION BY tbname,`location`"},
+			Vars:   nil,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tests.CheckBuildClauses(t, tc.Clauses, tc.Result, tc.Vars)
+		})
+	}
+}
+
+func Test_PartitionByEmpty(t *testing.T) {
+	db, _ := gorm.Open(tests.DummyDialector{}, nil)
+	stmt := &gorm.Statement{DB: db.Session(&gorm.Session{}), Clauses: map[string]clause.Clause{}}
+	stmt.AddClause(partition.PartitionBy{})
+	stmt.Build("PARTITION BY")
+	if stmt.DB.Error == nil {
+		t.Errorf("expect empty partition by error")
+	}
+}
@@ -0,0 +1,248 @@
+package stream
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/thinkgos/tdengine-gorm/clause/window"
+	"github.com/thinkgos/tdengine-gorm/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Trigger mode of the stream
+type Trigger string
+
+const (
+	AtOnce           Trigger = "AT_ONCE"
+	WindowClose      Trigger = "WINDOW_CLOSE"
+	MaxDelay         Trigger = "MAX_DELAY"
+	ForceWindowClose Trigger = "FORCE_WINDOW_CLOSE"
+)
+
+// CREATE STREAM [IF NOT EXISTS] stream_name [stream_options] INTO stb_name [(field_name, ...)] [SUBTABLE(expression)] AS subquery
+//
+// stream_options: {
+//  TRIGGER    [AT_ONCE | WINDOW_CLOSE | MAX_DELAY time | FORCE_WINDOW_CLOSE]
+//  WATERMARK   time
+//  IGNORE EXPIRED [0|1]
+//  DELETE_MARK time
+//  FILL_HISTORY [0|1] [ASYNC]
+//  IGNORE UPDATE [0|1]
+// }
+
+// Stream create stream clause, the body is a query built with the window and partition clauses.
+//
+//	query := db.Table("meters").Select("_wstart,avg(`current`)").
+//		Clauses(partition.SetPartitionBy().TbName(), window.SetInterval(window.Duration{Value: 1, Unit: window.Minute}))
+//	db.Migrator().(tdengine_gorm.Migrator).CreateStream(stream.NewStream("avg_current", "avg_current_1m", query).Trigger(stream.WindowClose))
+type Stream struct {
+	name          string
+	ifNotExists   bool
+	trigger       Trigger
+	maxDelay      *window.Duration
+	watermark     *window.Duration
+	ignoreExpired *bool
+	deleteMark    *window.Duration
+	fillHistory   *bool
+	async         bool
+	ignoreUpdate  *bool
+	into          string
+	columns       []string
+	subTable      clause.Expression
+	query         *gorm.DB
+}
+
+// NewStream create a stream which writes the result of the query into the super table
+func NewStream(name, into string, query *gorm.DB) Stream {
+	return Stream{name: name, into: into, query: query}
+}
+
+// IfNotExists [IF NOT EXISTS]
+func (s Stream) IfNotExists() Stream {
+	s.ifNotExists = true
+	return s
+}
+
+// Trigger trigger mode [TRIGGER AT_ONCE | WINDOW_CLOSE | FORCE_WINDOW_CLOSE], use SetMaxDelay for MAX_DELAY.
+func (s Stream) Trigger(trigger Trigger) Stream {
+	s.trigger = trigger
+	s.maxDelay = nil
+	return s
+}
+
+// SetMaxDelay trigger at window close or after the max delay [TRIGGER MAX_DELAY time]
+func (s Stream) SetMaxDelay(delay window.Duration) Stream {
+	s.trigger = MaxDelay
+	s.maxDelay = &delay
+	return s
+}
+
+// SetWatermark [WATERMARK time]
+func (s Stream) SetWatermark(watermark window.Duration) Stream {
+	s.watermark = &watermark
+	return s
+}
+
+// IgnoreExpired [IGNORE EXPIRED 0|1]
+func (s Stream) IgnoreExpired(ignore bool) Stream {
+	s.ignoreExpired = &ignore
+	return s
+}
+
+// SetDeleteMark [DELETE_MARK time]
+func (s Stream) SetDeleteMark(mark window.Duration) Stream {
+	s.deleteMark = &mark
+	return s
+}
+
+// FillHistory [FILL_HISTORY 0|1]
+func (s Stream) FillHistory(fill bool) Stream {
+	s.fillHistory = &fill
+	s.async = false
+	return s
+}
+
+// FillHistoryAsync fill history asynchronously [FILL_HISTORY 1 ASYNC]
+func (s Stream) FillHistoryAsync() Stream {
+	fill := true
+	s.fillHistory = &fill
+	s.async = true
+	return s
+}
+
+// IgnoreUpdate [IGNORE UPDATE 0|1]
+func (s Stream) IgnoreUpdate(ignore bool) Stream {
+	s.ignoreUpdate = &ignore
+	return s
+}
+
+// Columns columns of the target super table [(field_name, ...)]
+func (s Stream) Columns(columns ...string) Stream {
+	s.columns = columns
+	return s
+}
+
+// SubTable name of the child table of each partition [SUBTABLE(expression)]
+func (s Stream) SubTable(expr clause.Expression) Stream {
+	s.subTable = expr
+	return s
+}
+
+func (s Stream) Name() string {
+	return "CREATE STREAM"
+}
+
+// MergeClause merge CREATE STREAM clause
+func (s Stream) MergeClause(c *clause.Clause) {
+	c.Name = ""
+	c.Expression = s
+}
+
+func (s Stream) Build(builder clause.Builder) {
+	if err := s.validate(); err != nil {
+		_ = builder.AddError(err)
+		return
+	}
+	// gorm drops the error of the query built as a subquery.
+	if err := utils.SubqueryError(s.query); err != nil {
+		_ = builder.AddError(fmt.Errorf("stream: query: %w", err))
+		return
+	}
+	_, _ = builder.WriteString("CREATE STREAM ")
+	if s.ifNotExists {
+		_, _ = builder.WriteString("IF NOT EXISTS ")
+	}
+	builder.WriteQuoted(s.name)
+	if s.trigger != "" {
+		_, _ = builder.WriteString(" TRIGGER ")
+		_, _ = builder.WriteString(string(s.trigger))
+		if s.maxDelay != nil {
+			_ = builder.WriteByte(' ')
+			_, _ = builder.WriteString(s.maxDelay.String())
+		}
+	}
+	if s.watermark != nil {
+		_, _ = builder.WriteString(" WATERMARK ")
+		_, _ = builder.WriteString(s.watermark.String())
+	}
+	if s.ignoreExpired != nil {
+		_, _ = builder.WriteString(" IGNORE EXPIRED ")
+		writeBool(builder, *s.ignoreExpired)
+	}
+	if s.deleteMark != nil {
+		_, _ = builder.WriteString(" DELETE_MARK ")
+		_, _ = builder.WriteString(s.deleteMark.String())
+	}
+	if s.fillHistory != nil {
+		_, _ = builder.WriteString(" FILL_HISTORY ")
+		writeBool(builder, *s.fillHistory)
+		if s.async {
+			_, _ = builder.WriteString(" ASYNC")
+		}
+	}
+	if s.ignoreUpdate != nil {
+		_, _ = builder.WriteString(" IGNORE UPDATE ")
+		writeBool(builder, *s.ignoreUpdate)
+	}
+	_, _ = builder.WriteString(" INTO ")
+	builder.WriteQuoted(s.into)
+	if len(s.columns) > 0 {
+		_, _ = builder.WriteString(" (")
+		for i, column := range s.columns {
+			if i > 0 {
+				_ = builder.WriteByte(',')
+			}
+			builder.WriteQuoted(column)
+		}
+		_ = builder.WriteByte(')')
+	}
+	if s.subTable != nil {
+		_, _ = builder.WriteString(" SUBTABLE(")
+		s.subTable.Build(builder)
+		_ = builder.WriteByte(')')
+	}
+	_, _ = builder.WriteString(" AS ")
+	builder.AddVar(builder, s.query)
+}
+
+func (s Stream) validate() error {
+	if s.name == "" || s.into == "" {
+		return errors.New("stream: name and target table required")
+	}
+	if s.query == nil {
+		return errors.New("stream: query required")
+	}
+	switch s.trigger {
+	case "", AtOnce, WindowClose, ForceWindowClose:
+	case MaxDelay:
+		if s.maxDelay == nil {
+			return errors.New("stream: max delay trigger requires a duration")
+		}
+	default:
+		return fmt.Errorf("stream: unknown trigger %q", s.trigger)
+	}
+	for _, d := range []struct {
+		name     string
+		duration *window.Duration
+	}{
+		{"max delay", s.maxDelay},
+		{"watermark", s.watermark},
+		{"delete mark", s.deleteMark},
+	} {
+		if d.duration != nil {
+			if err := d.duration.Validate(false); err != nil {
+				return fmt.Errorf("stream: %s: %w", d.name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func writeBool(builder clause.Builder, v bool) {
+	if v {
+		_ = builder.WriteByte('1')
+	} else {
+		_ = builder.WriteByte('0')
+	}
+}
@@ -0,0 +1,82 @@
+package stream_test
+
+import (
+	"testing"
+
+	"github.com/thinkgos/tdengine-gorm/clause/partition"
+	"github.com/thinkgos/tdengine-gorm/clause/stream"
+	"github.com/thinkgos/tdengine-gorm/clause/tests"
+	"github.com/thinkgos/tdengine-gorm/clause/window"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func Test_Stream(t *testing.T) {
+	db, _ := gorm.Open(tests.DummyDialector{}, nil)
+	query := db.Table("meters").
+		Select("_wstart,avg(`current`)").
+		Where("`voltage` > ?", 200).
+		Clauses(partition.SetPartitionBy().TbName(), window.SetInterval(window.Duration{Value: 1, Unit: window.Minute}))
+
+	var testCases = []struct {
+		Name    string
+		Clauses []clause.Interface
+		Result  []string
+		Vars    [][][]any
+	}{
+		{
+			Name:    "default options",
+			Clauses: []clause.Interface{stream.NewStream("s_avg", "avg_1m", query)},
+			Result:  []string{"CREATE STREAM `s_avg` INTO `avg_1m` AS SELECT _wstart,avg(`current`) FROM `meters` WHERE `voltage` > ? PARTITION BY tbname INTERVAL(1m)"},
+			Vars:    [][][]any{{{200}}},
+		},
+		{
+			Name: "all options",
+			Clauses: []clause.Interface{
+				stream.NewStream("s_avg", "avg_1m", query).
+					IfNotExists().
+					SetMaxDelay(window.Duration{Value: 5, Unit: window.Second}).
+					SetWatermark(window.Duration{Value: 10, Unit: window.Second}).
+					IgnoreExpired(false).
+					SetDeleteMark(window.Duration{Value: 1, Unit: window.Day}).
+					FillHistoryAsync().
+					IgnoreUpdate(true).
+					Columns("ts", "avg_current").
+					SubTable(clause.Expr{SQL: "CONCAT('avg_', tbname)"}),
+			},
+			Result: []string{"CREATE STREAM IF NOT EXISTS `s_avg` TRIGGER MAX_DELAY 5s WATERMARK 10s IGNORE EXPIRED 0 DELETE_MARK 1d FILL_HISTORY 1 ASYNC IGNORE UPDATE 1 INTO `avg_1m` (`ts`,`avg_current`) SUBTABLE(CONCAT('avg_', tbname)) AS SELECT _wstart,avg(`current`) FROM `meters` WHERE `voltage` > ? PARTITION BY tbname INTERVAL(1m)"},
+			Vars:   [][][]any{{{200}}},
+		},
+		{
+			Name:    "trigger",
+			Clauses: []clause.Interface{stream.NewStream("s_avg", "avg_1m", query).Trigger(stream.WindowClose).FillHistory(false)},
+			Result:  []string{"CREATE STREAM `s_avg` TRIGGER WINDOW_CLOSE FILL_HISTORY 0 INTO `avg_1m` AS SELECT _wstart,avg(`current`) FROM `meters` WHERE `voltage` > ? PARTITION BY tbname INTERVAL(1m)"},
+			Vars:    [][][]any{{{200}}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tests.CheckBuildClauses(t, tc.Clauses, tc.Result, tc.Vars)
+		})
+	}
+}
+
+func Test_StreamInvalid(t *testing.T) {
+	db, _ := gorm.Open(tests.DummyDialector{}, nil)
+	query := db.Table("meters").Select("_wstart,avg(`current`)")
+	for name, s := range map[string]stream.Stream{
+		"no query":          stream.NewStream("s", "t", nil),
+		"max delay trigger": stream.NewStream("s", "t", query).Trigger(stream.MaxDelay),
+		"unknown trigger":   stream.NewStream("s", "t", query).Trigger("SOMETIME"),
+		"calendar mark":     stream.NewStream("s", "t", query).SetDeleteMark(window.Duration{Value: 1, Unit: window.Month}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			stmt := &gorm.Statement{DB: db.Session(&gorm.Session{}), Clauses: map[string]clause.Clause{}}
+			stmt.AddClause(s)
+			stmt.Build(s.Name())
+			if stmt.DB.Error == nil {
+				t.Errorf("expect error")
+			}
+		})
+	}
+}
@@ -60,3 +60,22 @@ func CheckBuildClauses(t *testing.T, clauses []clause.Interface, results []strin
 		t.Errorf("SQL \nexpects:\n\t%v\ngot:\n\t%v\n", results, sql)
 	}
 }
+
+// CheckBuildQuery check the SQL and vars of the query built in dry run mode.
+func CheckBuildQuery(t *testing.T, query func(tx *gorm.DB) *gorm.DB, result string, vars []any) {
+	t.Helper()
+	stmt := query(db.Session(&gorm.Session{DryRun: true})).Statement
+	if stmt.Error != nil {
+		t.Errorf("unexpected error: %v", stmt.Error)
+		return
+	}
+	sql := strings.TrimSpace(stmt.SQL.String())
+	if sql != result {
+		t.Errorf("SQL \nexpects:\n\t%v\ngot:\n\t%v\n", result, sql)
+	}
+	if len(stmt.Vars) > 0 || len(vars) > 0 {
+		if !reflect.DeepEqual(stmt.Vars, vars) {
+			t.Errorf("Vars \nexpects:\n\t%+v\ngot:\n\t%v\n", vars, stmt.Vars)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package tests
 import (
 	"github.com/thinkgos/tdengine-gorm/utils"
 	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
 	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
 	"gorm.io/gorm/schema"
@@ -14,7 +15,13 @@ func (DummyDialector) Name() string {
 	return "dummy"
 }
 
-func (DummyDialector) Initialize(*gorm.DB) error {
+// Initialize register the default callbacks with the same clauses order as the TDengine dialect.
+func (DummyDialector) Initialize(db *gorm.DB) error {
+	callbacks.RegisterDefaultCallbacks(db, &callbacks.Config{
+		LastInsertIDReversed: true,
+		QueryClauses:         utils.QueryClauses,
+		CreateClauses:        utils.CreateClauses,
+	})
 	return nil
 }
 
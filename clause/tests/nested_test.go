@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thinkgos/tdengine-gorm/clause/fill"
+	"github.com/thinkgos/tdengine-gorm/clause/partition"
+	"github.com/thinkgos/tdengine-gorm/clause/slimit"
+	"github.com/thinkgos/tdengine-gorm/clause/window"
+	"gorm.io/gorm"
+)
+
+func Test_NestedWindowQuery(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	minute := window.Duration{Value: 1, Unit: window.Minute}
+	hour := window.Duration{Value: 1, Unit: window.Hour}
+
+	inner := func(tx *gorm.DB) *gorm.DB {
+		return tx.Table("meters").
+			Select("_wstart AS `ts`,avg(`current`) AS `v`").
+			Where("`ts` >= ?", start).
+			Clauses(
+				partition.PartitionBy{}.TbName(),
+				window.SetInterval(minute),
+			)
+	}
+
+	testCases := []struct {
+		name   string
+		query  func(tx *gorm.DB) *gorm.DB
+		result string
+		vars   []any
+	}{
+		{
+			name: "interval over sub query",
+			query: func(tx *gorm.DB) *gorm.DB {
+				return tx.Table("(?) AS `t`", inner(tx)).
+					Select("_wstart,avg(`v`)").
+					Clauses(window.SetInterval(hour)).
+					Find(&[]map[string]any{})
+			},
+			result: "SELECT _wstart,avg(`v`) FROM (SELECT _wstart AS `ts`,avg(`current`) AS `v` FROM `meters` WHERE `ts` >= ? PARTITION BY tbname INTERVAL(1m)) AS `t` INTERVAL(1h)",
+			vars:   []any{start},
+		},
+		{
+			name: "fill and slimit over sub query",
+			query: func(tx *gorm.DB) *gorm.DB {
+				return tx.Table("(?) AS `t`", inner(tx).Clauses(fill.Fill{Type: fill.FillPrev})).
+					Select("_wstart,max(`v`)").
+					Where("`v` > ?", 10).
+					Clauses(
+						window.SetInterval(hour),
+						fill.SetValues(0),
+						slimit.SLimit{Limit: 5},
+					).
+					Limit(100).
+					Find(&[]map[string]any{})
+			},
+			result: "SELECT _wstart,max(`v`) FROM (SELECT _wstart AS `ts`,avg(`current`) AS `v` FROM `meters` WHERE `ts` >= ? PARTITION BY tbname INTERVAL(1m) FILL (PREV)) AS `t` WHERE `v` > ? INTERVAL(1h) FILL (VALUE,?) SLIMIT 5 LIMIT ?",
+			vars:   []any{start, 10, 0, 100},
+		},
+		{
+			name: "three levels",
+			query: func(tx *gorm.DB) *gorm.DB {
+				middle := tx.Table("(?) AS `t1`", inner(tx)).
+					Select("_wstart AS `ts`,max(`v`) AS `v`").
+					Clauses(window.SetInterval(hour))
+				return tx.Table("(?) AS `t2`", middle).
+					Select("_wstart,avg(`v`)").
+					Clauses(window.SetInterval(window.Duration{Value: 1, Unit: window.Day})).
+					Find(&[]map[string]any{})
+			},
+			result: "SELECT _wstart,avg(`v`) FROM (SELECT _wstart AS `ts`,max(`v`) AS `v` FROM (SELECT _wstart AS `ts`,avg(`current`) AS `v` FROM `meters` WHERE `ts` >= ? PARTITION BY tbname INTERVAL(1m)) AS `t1` INTERVAL(1h)) AS `t2` INTERVAL(1d)",
+			vars:   []any{start},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			CheckBuildQuery(t, tc.query, tc.result, tc.vars)
+		})
+	}
+}
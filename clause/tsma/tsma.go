@@ -0,0 +1,125 @@
+package tsma
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/thinkgos/tdengine-gorm/clause/fn"
+	"github.com/thinkgos/tdengine-gorm/clause/window"
+	"gorm.io/gorm/clause"
+)
+
+// CREATE TSMA [IF NOT EXISTS] tsma_name ON [db_name.]table_name FUNCTION(func_name(func_param) [, ...]) INTERVAL(time_duration)
+// CREATE RECURSIVE TSMA [IF NOT EXISTS] tsma_name ON [db_name.]tsma_name1 INTERVAL(time_duration)
+
+// TSMA create tsma clause, time-range small materialized aggregates of the table.
+//
+//	tsma.NewTSMA("meters_1h", "meters", window.Duration{Value: 1, Unit: window.Hour}, fn.Avg("current"), fn.Max("voltage"))
+//	tsma.NewRecursiveTSMA("meters_1d", "meters_1h", window.Duration{Value: 1, Unit: window.Day})
+type TSMA struct {
+	name        string
+	ifNotExists bool
+	recursive   bool
+	on          string
+	funcs       []fn.Func
+	interval    window.Duration
+}
+
+// NewTSMA create a tsma of the table with the aggregate functions without alias,
+// supported functions are fn.Avg, fn.Count, fn.First, fn.Last, fn.Max, fn.Min, fn.Spread, fn.Stddev and fn.Sum.
+func NewTSMA(name, table string, interval window.Duration, funcs ...fn.Func) TSMA {
+	return TSMA{name: name, on: table, interval: interval, funcs: funcs}
+}
+
+// NewRecursiveTSMA create a tsma on the base tsma with the same functions,
+// the interval must be a multiple of the interval of the base tsma.
+func NewRecursiveTSMA(name, base string, interval window.Duration) TSMA {
+	return TSMA{name: name, on: base, interval: interval, recursive: true}
+}
+
+// IfNotExists [IF NOT EXISTS]
+func (t TSMA) IfNotExists() TSMA {
+	t.ifNotExists = true
+	return t
+}
+
+func (t TSMA) Name() string {
+	return "CREATE TSMA"
+}
+
+// MergeClause merge CREATE TSMA clause
+func (t TSMA) MergeClause(c *clause.Clause) {
+	c.Name = ""
+	c.Expression = t
+}
+
+func (t TSMA) Build(builder clause.Builder) {
+	if err := t.validate(); err != nil {
+		_ = builder.AddError(err)
+		return
+	}
+	_, _ = builder.WriteString("CREATE ")
+	if t.recursive {
+		_, _ = builder.WriteString("RECURSIVE ")
+	}
+	_, _ = builder.WriteString("TSMA ")
+	if t.ifNotExists {
+		_, _ = builder.WriteString("IF NOT EXISTS ")
+	}
+	builder.WriteQuoted(t.name)
+	_, _ = builder.WriteString(" ON ")
+	builder.WriteQuoted(t.on)
+	if !t.recursive {
+		_, _ = builder.WriteString(" FUNCTION(")
+		for i, f := range t.funcs {
+			if i > 0 {
+				_ = builder.WriteByte(',')
+			}
+			f.Build(builder)
+		}
+		_ = builder.WriteByte(')')
+	}
+	_, _ = builder.WriteString(" INTERVAL(")
+	_, _ = builder.WriteString(t.interval.String())
+	_ = builder.WriteByte(')')
+}
+
+// functions the aggregate functions supported by tsma.
+var functions = map[string]struct{}{
+	"AVG": {}, "COUNT": {}, "FIRST": {}, "LAST": {}, "MAX": {}, "MIN": {}, "SPREAD": {}, "STDDEV": {}, "SUM": {},
+}
+
+// validate the interval is at least one minute, functions are required except recursive tsma.
+func (t TSMA) validate() error {
+	if t.name == "" || t.on == "" {
+		return errors.New("tsma: name and table required")
+	}
+	if t.recursive && len(t.funcs) > 0 {
+		return errors.New("tsma: recursive tsma uses the functions of the base tsma")
+	}
+	if !t.recursive && len(t.funcs) == 0 {
+		return errors.New("tsma: functions required")
+	}
+	for _, f := range t.funcs {
+		if _, ok := functions[f.FuncName()]; !ok {
+			return fmt.Errorf("tsma: unsupported function %s", f.FuncName())
+		}
+		if f.Alias() != "" {
+			return fmt.Errorf("tsma: function %s can not be aliased", f.FuncName())
+		}
+	}
+	if err := t.interval.Validate(true); err != nil {
+		return fmt.Errorf("tsma: interval: %w", err)
+	}
+	if !t.interval.IsCalendar() {
+		d, err := t.interval.ToDuration()
+		if err != nil {
+			return fmt.Errorf("tsma: interval: %w", err)
+		}
+		if d < time.Minute {
+			return fmt.Errorf("tsma: interval %s less than 1m", t.interval)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package tsma_test
+
+import (
+	"testing"
+
+	"github.com/thinkgos/tdengine-gorm/clause/fn"
+	"github.com/thinkgos/tdengine-gorm/clause/tests"
+	"github.com/thinkgos/tdengine-gorm/clause/tsma"
+	"github.com/thinkgos/tdengine-gorm/clause/window"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func Test_TSMA(t *testing.T) {
+	hour := window.Duration{Value: 1, Unit: window.Hour}
+	var testCases = []struct {
+		Name    string
+		Clauses []clause.Interface
+		Result  []string
+		Vars    [][][]any
+	}{
+		{
+			Name:    "tsma",
+			Clauses: []clause.Interface{tsma.NewTSMA("meters_1h", "power.meters", hour, fn.Avg("current"), fn.Max("voltage"), fn.Count(fn.All)).IfNotExists()},
+			Result:  []string{"CREATE TSMA IF NOT EXISTS `meters_1h` ON `power`.`meters` FUNCTION(AVG(`current`),MAX(`voltage`),COUNT(*)) INTERVAL(1h)"},
+			Vars:    nil,
+		},
+		{
+			Name:    "recursive",
+			Clauses: []clause.Interface{tsma.NewRecursiveTSMA("meters_1d", "meters_1h", window.Duration{Value: 1, Unit: window.Day})},
+			Result:  []string{"CREATE RECURSIVE TSMA `meters_1d` ON `meters_1h` INTERVAL(1d)"},
+			Vars:    nil,
+		},
+		{
+			Name:    "seconds interval",
+			Clauses: []clause.Interface{tsma.NewTSMA("meters_2m", "meters", window.Duration{Value: 120, Unit: window.Second}, fn.Avg("current"))},
+			Result:  []string{"CREATE TSMA `meters_2m` ON `meters` FUNCTION(AVG(`current`)) INTERVAL(120s)"},
+			Vars:    nil,
+		},
+		{
+			Name:    "milliseconds interval",
+			Clauses: []clause.Interface{tsma.NewTSMA("meters_1h", "meters", window.Duration{Value: 3600000, Unit: window.Millisecond}, fn.Avg("current"))},
+			Result:  []string{"CREATE TSMA `meters_1h` ON `meters` FUNCTION(AVG(`current`)) INTERVAL(3600000a)"},
+			Vars:    nil,
+		},
+		{
+			Name:    "calendar interval",
+			Clauses: []clause.Interface{tsma.NewRecursiveTSMA("meters_1n", "meters_1d", window.Duration{Value: 1, Unit: window.Month})},
+			Result:  []string{"CREATE RECURSIVE TSMA `meters_1n` ON `meters_1d` INTERVAL(1n)"},
+			Vars:    nil,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tests.CheckBuildClauses(t, tc.Clauses, tc.Result, tc.Vars)
+		})
+	}
+}
+
+func Test_TSMAInvalid(t *testing.T) {
+	db, _ := gorm.Open(tests.DummyDialector{}, nil)
+	hour := window.Duration{Value: 1, Unit: window.Hour}
+	for name, c := range map[string]tsma.TSMA{
+		"no function":          tsma.NewTSMA("t", "meters", hour),
+		"short interval":       tsma.NewTSMA("t", "meters", window.Duration{Value: 30, Unit: window.Second}, fn.Avg("current")),
+		"invalid function":     tsma.NewTSMA("t", "meters", hour, fn.Mavg("current", 0)),
+		"unsupported function": tsma.NewTSMA("t", "meters", hour, fn.Twa("current")),
+		"aliased function":     tsma.NewTSMA("t", "meters", hour, fn.Avg("current").As("avg_current")),
+		"zero interval":        tsma.NewTSMA("t", "meters", window.Duration{Unit: window.Hour}, fn.Avg("current")),
+		"no base tsma name":    tsma.NewRecursiveTSMA("t", "", hour),
+	} {
+		t.Run(name, func(t *testing.T) {
+			stmt := &gorm.Statement{DB: db.Session(&gorm.Session{}), Clauses: map[string]clause.Clause{}}
+			stmt.AddClause(c)
+			stmt.Build(c.Name())
+			if stmt.DB.Error == nil {
+				t.Errorf("expect error")
+			}
+		})
+	}
+}
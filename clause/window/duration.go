@@ -2,14 +2,18 @@ package window
 
 import (
 	"errors"
+	"fmt"
+	"math"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type UnitType string
 
-// u(微秒)、a(毫秒)、s(秒)、m(分)、h(小时)、d(天)、w(周) n(自然月) 和 y(自然年)
+// b(纳秒)、u(微秒)、a(毫秒)、s(秒)、m(分)、h(小时)、d(天)、w(周) n(自然月) 和 y(自然年)
 const (
+	Nanosecond  UnitType = "b"
 	Microsecond UnitType = "u"
 	Millisecond UnitType = "a"
 	Second      UnitType = "s"
@@ -22,6 +26,7 @@ const (
 )
 
 var durationMap = map[UnitType]struct{}{
+	Nanosecond:  {},
 	Microsecond: {},
 	Millisecond: {},
 	Second:      {},
@@ -33,37 +38,164 @@ var durationMap = map[UnitType]struct{}{
 	Year:        {},
 }
 
+// fixedUnits units with fixed length, from the largest to the smallest.
+var fixedUnits = []struct {
+	unit UnitType
+	d    time.Duration
+}{
+	{Week, 7 * 24 * time.Hour},
+	{Day, 24 * time.Hour},
+	{Hour, time.Hour},
+	{Minute, time.Minute},
+	{Second, time.Second},
+	{Millisecond, time.Millisecond},
+	{Microsecond, time.Microsecond},
+	{Nanosecond, time.Nanosecond},
+}
+
+// unitAlias multi-character and go style unit, matched case-insensitively.
+var unitAlias = map[string]UnitType{
+	"ns":  Nanosecond,
+	"us":  Microsecond,
+	"µs":  Microsecond,
+	"ms":  Millisecond,
+	"sec": Second,
+	"min": Minute,
+	"hr":  Hour,
+	"mo":  Month,
+	"yr":  Year,
+}
+
 type Duration struct {
 	Value uint64
 	Unit  UnitType
 }
 
+// NewDuration new duration from time.Duration, with the largest unit which represents it exactly,
+// e.g. 1 hour is 1h, 1500 milliseconds is 1500a.
 func NewDuration(duration time.Duration) (*Duration, error) {
 	if duration <= 0 {
 		return nil, errors.New("duration does not allow negative numbers")
 	}
-	return &Duration{
-		Value: uint64(duration.Microseconds()),
-		Unit:  Microsecond,
-	}, nil
+	for _, u := range fixedUnits {
+		if duration%u.d == 0 {
+			return &Duration{
+				Value: uint64(duration / u.d),
+				Unit:  u.unit,
+			}, nil
+		}
+	}
+	// unreachable, every duration is a multiple of nanosecond.
+	return &Duration{Value: uint64(duration), Unit: Nanosecond}, nil
 }
 
+// ParseDuration parse the duration string, e.g. 5m, 10ms, 1H, 1h30m.
+// units are case-insensitive except M, which is rejected as ambiguous, go style units ns, us, µs and ms are supported.
+// multiple parts are summed up with the largest exact unit, month and year can not be mixed with others.
 func ParseDuration(durationString string) (*Duration, error) {
-	if len(durationString) < 2 {
+	s := strings.TrimSpace(durationString)
+	if len(s) < 2 {
 		return nil, errors.New("parse duration failure")
 	}
-	unit := UnitType(durationString[len(durationString)-1:])
-	_, valid := durationMap[unit]
-	if !valid {
-		return nil, errors.New("unit not valid")
+	var parts []Duration
+	for s != "" {
+		i := 0
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		if i == 0 {
+			return nil, fmt.Errorf("parse duration %q failure", durationString)
+		}
+		v, err := strconv.ParseUint(s[:i], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		s = s[i:]
+		j := 0
+		for j < len(s) && (s[j] < '0' || s[j] > '9') {
+			j++
+		}
+		unit, err := parseUnit(s[:j])
+		if err != nil {
+			return nil, err
+		}
+		s = s[j:]
+		parts = append(parts, Duration{Value: v, Unit: unit})
+	}
+	if len(parts) == 1 {
+		return &parts[0], nil
+	}
+	var total time.Duration
+	for _, p := range parts {
+		d, err := p.ToDuration()
+		if err != nil {
+			return nil, fmt.Errorf("parse duration %q failure: %w", durationString, err)
+		}
+		if total > math.MaxInt64-d {
+			return nil, fmt.Errorf("parse duration %q failure: overflow", durationString)
+		}
+		total += d
+	}
+	return NewDuration(total)
+}
+
+func parseUnit(s string) (UnitType, error) {
+	if s == "" {
+		return "", errors.New("unit not valid")
+	}
+	// M is minute in TDengine but month in other notations, use m or n instead.
+	if s == "M" {
+		return "", errors.New("unit M is ambiguous, use m for minute or n for month")
+	}
+	lower := strings.ToLower(s)
+	if unit, ok := unitAlias[lower]; ok {
+		return unit, nil
+	}
+	unit := UnitType(lower)
+	if _, valid := durationMap[unit]; !valid {
+		return "", errors.New("unit not valid")
+	}
+	return unit, nil
+}
+
+// String the duration in TDengine form, e.g. 5m, it can be parsed by ParseDuration.
+func (d Duration) String() string {
+	return strconv.FormatUint(d.Value, 10) + string(d.Unit)
+}
+
+// IsCalendar report whether the unit is calendar-aware, month or year, which has no fixed length.
+func (d Duration) IsCalendar() bool {
+	return d.Unit == Month || d.Unit == Year
+}
+
+// ToDuration convert to time.Duration, month and year can not be converted.
+func (d Duration) ToDuration() (time.Duration, error) {
+	if d.IsCalendar() {
+		return 0, fmt.Errorf("calendar unit %s has no fixed length", d.Unit)
+	}
+	for _, u := range fixedUnits {
+		if u.unit == d.Unit {
+			if d.Value > uint64(math.MaxInt64/u.d) {
+				return 0, fmt.Errorf("duration %s overflow", d)
+			}
+			return time.Duration(d.Value) * u.d, nil
+		}
+	}
+	return 0, fmt.Errorf("unit %q not valid", d.Unit)
+}
+
+// Validate check the duration is positive with a valid unit,
+// calendar units (month and year) are only allowed if allowCalendar,
+// TDengine allows them in INTERVAL and SLIDING only.
+func (d Duration) Validate(allowCalendar bool) error {
+	if _, valid := durationMap[d.Unit]; !valid {
+		return fmt.Errorf("unit %q not valid", d.Unit)
+	}
+	if d.Value == 0 {
+		return errors.New("duration must be positive")
 	}
-	value := durationString[:len(durationString)-1]
-	v, err := strconv.ParseUint(value, 10, 64)
-	if err != nil {
-		return nil, err
+	if !allowCalendar && d.IsCalendar() {
+		return fmt.Errorf("unit %s not allowed", d.Unit)
 	}
-	return &Duration{
-		Value: v,
-		Unit:  unit,
-	}, nil
+	return nil
 }
@@ -0,0 +1,46 @@
+package window
+
+import (
+	"gorm.io/gorm/clause"
+)
+
+// PseudoColumn window pseudo column.
+//
+// Use PseudoColumn.Column in Select so that the result column has a stable name,
+// then scan it with a read-only field tagged with the same column name:
+//
+//	type Bucket struct {
+//		Start time.Time `gorm:"column:_wstart;->"`
+//		End   time.Time `gorm:"column:_wend;->"`
+//		Value float64
+//	}
+//
+//	db.Table("t_1").
+//		Select("?,?,max(`value`) AS `value`", window.WStart.Column(), window.WEnd.Column()).
+//		Clauses(window.SetInterval(Duration{Value: 1, Unit: Minute})).
+//		Find(&buckets)
+type PseudoColumn string
+
+const (
+	WStart    PseudoColumn = "_wstart"    // start time of the window
+	WEnd      PseudoColumn = "_wend"      // end time of the window
+	WDuration PseudoColumn = "_wduration" // duration of the window
+	QStart    PseudoColumn = "_qstart"    // start time of the query
+	QEnd      PseudoColumn = "_qend"      // end time of the query
+	IRowTs    PseudoColumn = "_irowts"    // timestamp of the row generated by INTERP
+)
+
+// Build write the pseudo column name.
+func (p PseudoColumn) Build(builder clause.Builder) {
+	_, _ = builder.WriteString(string(p))
+}
+
+// Column pseudo column aliased to its own name, keep the result column name independent of the driver.
+func (p PseudoColumn) Column() clause.Expression {
+	return p.As(string(p))
+}
+
+// As pseudo column with alias.
+func (p PseudoColumn) As(alias string) clause.Expression {
+	return clause.Expr{SQL: "? AS ?", Vars: []any{p, clause.Column{Name: alias}}}
+}
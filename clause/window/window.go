@@ -1,6 +1,8 @@
 package window
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
 
 	"gorm.io/gorm/clause"
@@ -10,19 +12,30 @@ const (
 	SESSION = iota + 1
 	STATE
 	INTERVAL
+	EVENT
+	COUNT
 )
 
 // [SESSION(ts_col, tol_val)]
-// [STATE_WINDOW(col)]
-// [INTERVAL(interval_val [, interval_offset]) [SLIDING sliding_val]]
+// [STATE_WINDOW(col) [TRUE_FOR(true_for_duration)]]
+// [INTERVAL(interval_val [, interval_offset | AUTO]) [SLIDING sliding_val]]
+// [EVENT_WINDOW START WITH start_trigger_condition END WITH end_trigger_condition [TRUE_FOR(true_for_duration)]]
+// [COUNT_WINDOW(count_val[, sliding_val])]
 
 type Window struct {
 	windowType  int
 	tsColumn    string
 	stateColumn string
+	expr        clause.Expression
 	duration    *Duration
 	offset      *Duration
+	autoOffset  bool
 	sliding     *Duration
+	trueFor     *Duration
+	startCond   clause.Expression
+	endCond     clause.Expression
+	count       uint64
+	countSlide  uint64
 }
 
 // SetSessionWindow create a session window [SESSION(ts_col, tol_val)]
@@ -30,20 +43,60 @@ func SetSessionWindow(tsColumn string, duration Duration) Window {
 	return Window{windowType: SESSION, tsColumn: tsColumn, duration: &duration}
 }
 
+// SetSessionWindowExpr create a session window on an expression [SESSION(expr, tol_val)], e.g. the _rowts pseudo column.
+func SetSessionWindowExpr(expr clause.Expression, duration Duration) Window {
+	return Window{windowType: SESSION, expr: expr, duration: &duration}
+}
+
 // SetStateWindow create a state window [STATE_WINDOW(col)]
 func SetStateWindow(column string) Window {
 	return Window{windowType: STATE, stateColumn: column}
 }
 
+// SetStateWindowExpr create a state window on an expression [STATE_WINDOW(expr)]
+//
+//	window.SetStateWindowExpr(clause.Expr{SQL: "CASE WHEN ? > ? THEN 1 ELSE 0 END", Vars: []any{clause.Column{Name: "voltage"}, 220}})
+func SetStateWindowExpr(expr clause.Expression) Window {
+	return Window{windowType: STATE, expr: expr}
+}
+
 // SetInterval create an interval window [INTERVAL(interval_val [, interval_offset]) [SLIDING sliding_val]]
 func SetInterval(duration Duration) Window {
 	return Window{windowType: INTERVAL, duration: &duration}
 }
 
+// SetEventWindow create an event window [EVENT_WINDOW START WITH start_trigger_condition END WITH end_trigger_condition]
+func SetEventWindow(start, end clause.Expression) Window {
+	return Window{windowType: EVENT, startCond: start, endCond: end}
+}
+
+// SetCountWindow create a count window [COUNT_WINDOW(count_val)]
+func SetCountWindow(count uint64) Window {
+	return Window{windowType: COUNT, count: count}
+}
+
+// SetCountSliding set sliding to count window [COUNT_WINDOW(count_val, sliding_val)], sliding must not exceed count
+func (sc Window) SetCountSliding(sliding uint64) Window {
+	if sc.windowType == COUNT {
+		sc.countSlide = sliding
+	}
+	return sc
+}
+
 // SetOffset set offset to interval window
 func (sc Window) SetOffset(offset Duration) Window {
 	if sc.windowType == INTERVAL {
 		sc.offset = &offset
+		sc.autoOffset = false
+	}
+	return sc
+}
+
+// SetAutoOffset align the interval window to the start time of the query [INTERVAL(interval_val, AUTO)]
+func (sc Window) SetAutoOffset() Window {
+	if sc.windowType == INTERVAL {
+		sc.offset = nil
+		sc.autoOffset = true
 	}
 	return sc
 }
@@ -56,36 +109,142 @@ func (sc Window) SetSliding(sliding Duration) Window {
 	return sc
 }
 
+// SetTrueFor keep the state or event window only if it lasts at least the duration [TRUE_FOR(true_for_duration)]
+func (sc Window) SetTrueFor(trueFor Duration) Window {
+	if sc.windowType == STATE || sc.windowType == EVENT {
+		sc.trueFor = &trueFor
+	}
+	return sc
+}
+
+// Durations the durations of the window, e.g. interval, offset, sliding, session tolerance and true for.
+func (sc Window) Durations() []Duration {
+	durations := make([]Duration, 0, 3)
+	for _, d := range []*Duration{sc.duration, sc.offset, sc.sliding, sc.trueFor} {
+		if d != nil {
+			durations = append(durations, *d)
+		}
+	}
+	return durations
+}
+
 func (sc Window) Build(builder clause.Builder) {
 	switch sc.windowType {
 	case SESSION:
+		if err := sc.duration.Validate(false); err != nil {
+			_ = builder.AddError(fmt.Errorf("session window: %w", err))
+			return
+		}
 		builder.WriteString("SESSION(")
-		builder.WriteQuoted(sc.tsColumn)
+		sc.writeOperand(builder, sc.tsColumn)
 		builder.WriteByte(',')
-		builder.WriteString(strconv.FormatUint(sc.duration.Value, 10))
-		builder.WriteString(string(sc.duration.Unit))
+		builder.WriteString(sc.duration.String())
 		builder.WriteByte(')')
 	case STATE:
+		if err := sc.validateTrueFor(); err != nil {
+			_ = builder.AddError(err)
+			return
+		}
 		builder.WriteString("STATE_WINDOW(")
-		builder.WriteQuoted(sc.stateColumn)
+		sc.writeOperand(builder, sc.stateColumn)
 		builder.WriteByte(')')
+		sc.writeTrueFor(builder)
 	case INTERVAL:
+		if err := sc.validateInterval(); err != nil {
+			_ = builder.AddError(err)
+			return
+		}
 		builder.WriteString("INTERVAL(")
-		builder.WriteString(strconv.FormatUint(sc.duration.Value, 10))
-		builder.WriteString(string(sc.duration.Unit))
-		if sc.offset != nil {
+		builder.WriteString(sc.duration.String())
+		if sc.autoOffset {
+			builder.WriteString(",AUTO")
+		} else if sc.offset != nil {
 			builder.WriteByte(',')
-			builder.WriteString(strconv.FormatUint(sc.offset.Value, 10))
-			builder.WriteString(string(sc.offset.Unit))
+			builder.WriteString(sc.offset.String())
 		}
 		builder.WriteByte(')')
 		if sc.sliding != nil {
 			builder.WriteString(" SLIDING(")
-			builder.WriteString(strconv.FormatUint(sc.sliding.Value, 10))
-			builder.WriteString(string(sc.sliding.Unit))
+			builder.WriteString(sc.sliding.String())
 			builder.WriteByte(')')
 		}
+	case EVENT:
+		if sc.startCond == nil || sc.endCond == nil {
+			_ = builder.AddError(errors.New("event window requires start and end condition"))
+			return
+		}
+		if err := sc.validateTrueFor(); err != nil {
+			_ = builder.AddError(err)
+			return
+		}
+		builder.WriteString("EVENT_WINDOW START WITH ")
+		sc.startCond.Build(builder)
+		builder.WriteString(" END WITH ")
+		sc.endCond.Build(builder)
+		sc.writeTrueFor(builder)
+	case COUNT:
+		if sc.count == 0 {
+			_ = builder.AddError(errors.New("count window requires a positive count"))
+			return
+		}
+		if sc.countSlide > sc.count {
+			_ = builder.AddError(fmt.Errorf("count window sliding %d greater than count %d", sc.countSlide, sc.count))
+			return
+		}
+		builder.WriteString("COUNT_WINDOW(")
+		builder.WriteString(strconv.FormatUint(sc.count, 10))
+		if sc.countSlide > 0 {
+			builder.WriteByte(',')
+			builder.WriteString(strconv.FormatUint(sc.countSlide, 10))
+		}
+		builder.WriteByte(')')
+	}
+}
+
+// writeOperand write the expression if any, otherwise the quoted column.
+func (sc Window) writeOperand(builder clause.Builder, column string) {
+	if sc.expr != nil {
+		sc.expr.Build(builder)
+		return
+	}
+	builder.WriteQuoted(column)
+}
+
+// validateTrueFor calendar units are not allowed in true for.
+func (sc Window) validateTrueFor() error {
+	if sc.trueFor == nil {
+		return nil
+	}
+	if err := sc.trueFor.Validate(false); err != nil {
+		return fmt.Errorf("true for: %w", err)
+	}
+	return nil
+}
+
+func (sc Window) writeTrueFor(builder clause.Builder) {
+	if sc.trueFor != nil {
+		builder.WriteString(" TRUE_FOR(")
+		builder.WriteString(sc.trueFor.String())
+		builder.WriteByte(')')
+	}
+}
+
+// validateInterval calendar units are allowed in interval and sliding, but not in offset.
+func (sc Window) validateInterval() error {
+	if err := sc.duration.Validate(true); err != nil {
+		return fmt.Errorf("interval: %w", err)
+	}
+	if sc.offset != nil && sc.offset.Value > 0 {
+		if err := sc.offset.Validate(false); err != nil {
+			return fmt.Errorf("interval offset: %w", err)
+		}
+	}
+	if sc.sliding != nil {
+		if err := sc.sliding.Validate(true); err != nil {
+			return fmt.Errorf("sliding: %w", err)
+		}
 	}
+	return nil
 }
 
 func (sc Window) Name() string {
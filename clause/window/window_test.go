@@ -7,6 +7,7 @@ import (
 	"github.com/thinkgos/tdengine-gorm/clause/tests"
 	"github.com/thinkgos/tdengine-gorm/clause/window"
 
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
@@ -53,6 +54,17 @@ func Test_SetStateWindow(t *testing.T) {
 			Result: []string{"SELECT avg(`t_1`.`value`) FROM `t_1` STATE_WINDOW(`state`)"},
 			Vars:   nil,
 		},
+		{
+			Name: "expression true for",
+			Clauses: []clause.Interface{
+				clause.Select{Columns: []clause.Column{{Name: "_wstart,count(*)", Raw: true}}},
+				clause.From{Tables: []clause.Table{{Name: "t_1"}}},
+				window.SetStateWindowExpr(clause.Expr{SQL: "CASE WHEN ? > ? THEN 1 ELSE 0 END", Vars: []any{clause.Column{Name: "voltage"}, 220}}).
+					SetTrueFor(window.Duration{Value: 3, Unit: window.Second}),
+			},
+			Result: []string{"SELECT _wstart,count(*) FROM `t_1` STATE_WINDOW(CASE WHEN `voltage` > ? THEN 1 ELSE 0 END) TRUE_FOR(3s)"},
+			Vars:   [][][]any{{{220}}},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -62,6 +74,18 @@ func Test_SetStateWindow(t *testing.T) {
 	}
 }
 
+func Test_TrueForCalendarUnit(t *testing.T) {
+	db, _ := gorm.Open(tests.DummyDialector{}, nil)
+	stmt := &gorm.Statement{DB: db.Session(&gorm.Session{}), Clauses: map[string]clause.Clause{}}
+	stmt.AddClause(clause.Select{Columns: []clause.Column{{Name: "count(*)", Raw: true}}})
+	stmt.AddClause(clause.From{Tables: []clause.Table{{Name: "t_1"}}})
+	stmt.AddClause(window.SetStateWindow("state").SetTrueFor(window.Duration{Value: 1, Unit: window.Month}))
+	stmt.Build("SELECT", "FROM", "WINDOW")
+	if stmt.DB.Error == nil {
+		t.Errorf("expect calendar unit error")
+	}
+}
+
 func Test_SetSessionWindow(t *testing.T) {
 	var testCases = []struct {
 		Name    string
@@ -82,6 +106,16 @@ func Test_SetSessionWindow(t *testing.T) {
 			Result: []string{"SELECT avg(`t_1`.`value`) FROM `t_1` SESSION(`ts`,10m)"},
 			Vars:   nil,
 		},
+		{
+			Name: "expression",
+			Clauses: []clause.Interface{
+				clause.Select{Columns: []clause.Column{{Name: "count(*)", Raw: true}}},
+				clause.From{Tables: []clause.Table{{Name: "t_1"}}},
+				window.SetSessionWindowExpr(clause.Expr{SQL: "_rowts"}, window.Duration{Value: 10, Unit: window.Second}),
+			},
+			Result: []string{"SELECT count(*) FROM `t_1` SESSION(_rowts,10s)"},
+			Vars:   nil,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -110,6 +144,16 @@ func Test_SetOffset(t *testing.T) {
 			Result: []string{"SELECT avg(`t_1`.`value`) FROM `t_1` INTERVAL(10m,5m)"},
 			Vars:   nil,
 		},
+		{
+			Name: "auto",
+			Clauses: []clause.Interface{
+				clause.Select{Columns: []clause.Column{{Name: "count(*)", Raw: true}}},
+				clause.From{Tables: []clause.Table{{Name: "t_1"}}},
+				window.SetInterval(window.Duration{Value: 1, Unit: window.Day}).SetAutoOffset(),
+			},
+			Result: []string{"SELECT count(*) FROM `t_1` INTERVAL(1d,AUTO)"},
+			Vars:   nil,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -151,6 +195,126 @@ func Test_SetSliding(t *testing.T) {
 	}
 }
 
+func Test_SetEventWindow(t *testing.T) {
+	var testCases = []struct {
+		Name    string
+		Clauses []clause.Interface
+		Result  []string
+		Vars    [][][]any
+	}{
+		{
+			Name: "",
+			Clauses: []clause.Interface{
+				clause.Select{Columns: []clause.Column{{Name: "_wstart,max(`t_1`.`temperature`)", Raw: true}}},
+				clause.From{Tables: []clause.Table{{Name: "t_1"}}},
+				window.SetEventWindow(
+					clause.Gt{Column: clause.Column{Name: "temperature"}, Value: 80},
+					clause.Lte{Column: clause.Column{Name: "temperature"}, Value: 75},
+				),
+			},
+			Result: []string{"SELECT _wstart,max(`t_1`.`temperature`) FROM `t_1` EVENT_WINDOW START WITH `temperature` > ? END WITH `temperature` <= ?"},
+			Vars:   [][][]any{{{80, 75}}},
+		},
+		{
+			Name: "true for",
+			Clauses: []clause.Interface{
+				clause.Select{Columns: []clause.Column{{Name: "count(*)", Raw: true}}},
+				clause.From{Tables: []clause.Table{{Name: "t_1"}}},
+				window.SetEventWindow(
+					clause.Gt{Column: clause.Column{Name: "temperature"}, Value: 80},
+					clause.Lte{Column: clause.Column{Name: "temperature"}, Value: 75},
+				).SetTrueFor(window.Duration{Value: 1, Unit: window.Minute}),
+			},
+			Result: []string{"SELECT count(*) FROM `t_1` EVENT_WINDOW START WITH `temperature` > ? END WITH `temperature` <= ? TRUE_FOR(1m)"},
+			Vars:   [][][]any{{{80, 75}}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tests.CheckBuildClauses(t, tc.Clauses, tc.Result, tc.Vars)
+		})
+	}
+}
+
+func Test_SetCountWindow(t *testing.T) {
+	var testCases = []struct {
+		Name    string
+		Clauses []clause.Interface
+		Result  []string
+		Vars    [][][]any
+	}{
+		{
+			Name: "count",
+			Clauses: []clause.Interface{
+				clause.Select{Columns: []clause.Column{{Name: "avg(`t_1`.`value`)", Raw: true}}},
+				clause.From{Tables: []clause.Table{{Name: "t_1"}}},
+				window.SetCountWindow(10),
+			},
+			Result: []string{"SELECT avg(`t_1`.`value`) FROM `t_1` COUNT_WINDOW(10)"},
+			Vars:   nil,
+		},
+		{
+			Name: "count with sliding",
+			Clauses: []clause.Interface{
+				clause.Select{Columns: []clause.Column{{Name: "avg(`t_1`.`value`)", Raw: true}}},
+				clause.From{Tables: []clause.Table{{Name: "t_1"}}},
+				window.SetCountWindow(10).SetCountSliding(5),
+			},
+			Result: []string{"SELECT avg(`t_1`.`value`) FROM `t_1` COUNT_WINDOW(10,5)"},
+			Vars:   nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tests.CheckBuildClauses(t, tc.Clauses, tc.Result, tc.Vars)
+		})
+	}
+}
+
+func Test_CountSlidingGreaterThanCount(t *testing.T) {
+	db, _ := gorm.Open(tests.DummyDialector{}, nil)
+	stmt := &gorm.Statement{DB: db.Session(&gorm.Session{}), Clauses: map[string]clause.Clause{}}
+	stmt.AddClause(clause.Select{Columns: []clause.Column{{Name: "count(*)", Raw: true}}})
+	stmt.AddClause(clause.From{Tables: []clause.Table{{Name: "t_1"}}})
+	stmt.AddClause(window.SetCountWindow(5).SetCountSliding(10))
+	stmt.Build("SELECT", "FROM", "WINDOW")
+	if stmt.DB.Error == nil {
+		t.Errorf("expect sliding greater than count error")
+	}
+}
+
+func Test_PseudoColumn(t *testing.T) {
+	var testCases = []struct {
+		Name    string
+		Clauses []clause.Interface
+		Result  []string
+		Vars    [][][]any
+	}{
+		{
+			Name: "select",
+			Clauses: []clause.Interface{
+				clause.Select{Expression: clause.Expr{
+					SQL:  "?,?,?,max(`value`) AS `value`",
+					Vars: []any{window.WStart.Column(), window.WEnd.As("end"), window.WDuration},
+				}},
+				clause.From{Tables: []clause.Table{{Name: "t_1"}}},
+				window.SetInterval(window.Duration{Value: 10, Unit: window.Minute}),
+				clause.OrderBy{Expression: clause.Expr{SQL: "? DESC", Vars: []any{window.WStart}}},
+			},
+			Result: []string{"SELECT _wstart AS `_wstart`,_wend AS `end`,_wduration,max(`value`) AS `value` FROM `t_1` INTERVAL(10m) ORDER BY _wstart DESC"},
+			Vars:   nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tests.CheckBuildClauses(t, tc.Clauses, tc.Result, tc.Vars)
+		})
+	}
+}
+
 func Test_NewDuration(t *testing.T) {
 	duration5Min, err := window.NewDuration(time.Minute * 5)
 	if err != nil {
@@ -175,7 +339,7 @@ func Test_NewDuration(t *testing.T) {
 				clause.From{Tables: []clause.Table{{Name: "t_1"}}},
 				window.SetInterval(*duration5Min),
 			},
-			Result: []string{"SELECT avg(`t_1`.`value`) FROM `t_1` INTERVAL(300000000u)"},
+			Result: []string{"SELECT avg(`t_1`.`value`) FROM `t_1` INTERVAL(5m)"},
 			Vars:   nil,
 		},
 	}
@@ -231,3 +395,122 @@ func Test_ParseDuration(t *testing.T) {
 		})
 	}
 }
+
+func Test_NewDurationUnit(t *testing.T) {
+	testCases := []struct {
+		d    time.Duration
+		want string
+	}{
+		{time.Hour, "1h"},
+		{90 * time.Minute, "90m"},
+		{1500 * time.Millisecond, "1500a"},
+		{2 * time.Microsecond, "2u"},
+		{1500 * time.Nanosecond, "1500b"},
+		{14 * 24 * time.Hour, "2w"},
+		{36 * time.Hour, "36h"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.want, func(t *testing.T) {
+			d, err := window.NewDuration(tc.d)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := d.String(); got != tc.want {
+				t.Errorf("expect %s, got %s", tc.want, got)
+			}
+			back, err := d.ToDuration()
+			if err != nil || back != tc.d {
+				t.Errorf("expect %v, got %v, %v", tc.d, back, err)
+			}
+			parsed, err := window.ParseDuration(d.String())
+			if err != nil || *parsed != *d {
+				t.Errorf("round trip %s, got %v, %v", d, parsed, err)
+			}
+		})
+	}
+}
+
+func Test_ParseDurationForms(t *testing.T) {
+	testCases := []struct {
+		s    string
+		want window.Duration
+	}{
+		{"10ms", window.Duration{Value: 10, Unit: window.Millisecond}},
+		{"3US", window.Duration{Value: 3, Unit: window.Microsecond}},
+		{"7ns", window.Duration{Value: 7, Unit: window.Nanosecond}},
+		{"7b", window.Duration{Value: 7, Unit: window.Nanosecond}},
+		{"1H", window.Duration{Value: 1, Unit: window.Hour}},
+		{"2min", window.Duration{Value: 2, Unit: window.Minute}},
+		{"1h30m", window.Duration{Value: 90, Unit: window.Minute}},
+		{"1s500ms", window.Duration{Value: 1500, Unit: window.Millisecond}},
+		{"1N", window.Duration{Value: 1, Unit: window.Month}},
+		{"1y", window.Duration{Value: 1, Unit: window.Year}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.s, func(t *testing.T) {
+			got, err := window.ParseDuration(tc.s)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if *got != tc.want {
+				t.Errorf("expect %v, got %v", tc.want, *got)
+			}
+		})
+	}
+	for _, s := range []string{"1y1d", "1h1", "h1", "1xs", "1M", "1h1M"} {
+		if _, err := window.ParseDuration(s); err == nil {
+			t.Errorf("%s: need error", s)
+		}
+	}
+	if _, err := (window.Duration{Value: 1, Unit: window.Month}).ToDuration(); err == nil {
+		t.Errorf("month: need error")
+	}
+}
+
+func Test_DurationValidate(t *testing.T) {
+	month := window.Duration{Value: 1, Unit: window.Month}
+	if err := month.Validate(true); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := month.Validate(false); err == nil {
+		t.Errorf("need error")
+	}
+	if err := (window.Duration{Unit: window.Second}).Validate(true); err == nil {
+		t.Errorf("need error")
+	}
+	if err := (window.Duration{Value: 1, Unit: "x"}).Validate(true); err == nil {
+		t.Errorf("need error")
+	}
+	var testCases = []struct {
+		Name    string
+		Clauses []clause.Interface
+		Result  []string
+		Vars    [][][]any
+	}{
+		{
+			Name: "calendar interval",
+			Clauses: []clause.Interface{
+				clause.Select{Columns: []clause.Column{{Name: "avg(`t_1`.`value`)", Raw: true}}},
+				clause.From{Tables: []clause.Table{{Name: "t_1"}}},
+				window.SetInterval(month).SetSliding(month),
+			},
+			Result: []string{"SELECT avg(`t_1`.`value`) FROM `t_1` INTERVAL(1n) SLIDING(1n)"},
+			Vars:   nil,
+		},
+		{
+			Name: "calendar session",
+			Clauses: []clause.Interface{
+				clause.Select{Columns: []clause.Column{{Name: "avg(`t_1`.`value`)", Raw: true}}},
+				clause.From{Tables: []clause.Table{{Name: "t_1"}}},
+				window.SetSessionWindow("ts", month),
+			},
+			Result: []string{"SELECT avg(`t_1`.`value`) FROM `t_1`"},
+			Vars:   nil,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tests.CheckBuildClauses(t, tc.Clauses, tc.Result, tc.Vars)
+		})
+	}
+}
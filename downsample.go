@@ -0,0 +1,24 @@
+package tdengine_gorm
+
+import (
+	"errors"
+
+	"github.com/thinkgos/tdengine-gorm/clause/window"
+	"gorm.io/gorm"
+)
+
+// Downsample two-level downsampling, re-aggregate the result of the windowed inner query by the outer interval
+// [SELECT ... FROM (inner) AS `t` INTERVAL(interval)].
+// The inner query must select the window start as the timestamp column, e.g. `_wstart AS ts`.
+//
+//	inner := db.Table("meters").
+//		Select("_wstart AS `ts`,avg(`current`) AS `v`").
+//		Clauses(partition.PartitionBy{}.TbName(), window.SetInterval(minute))
+//	Downsample(db, inner, hour).Select("_wstart,avg(`v`)").Find(&rows)
+func Downsample(db, inner *gorm.DB, interval window.Duration) *gorm.DB {
+	tx := db.Table("(?) AS `t`", inner).Clauses(window.SetInterval(interval))
+	if _, ok := inner.Statement.Clauses["WINDOW"]; !ok {
+		_ = tx.AddError(errors.New("tdengine: downsample requires a windowed inner query"))
+	}
+	return tx
+}
@@ -0,0 +1,40 @@
+package tdengine_gorm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/thinkgos/tdengine-gorm/clause/fill"
+	"github.com/thinkgos/tdengine-gorm/clause/partition"
+	"github.com/thinkgos/tdengine-gorm/clause/window"
+	"gorm.io/gorm"
+)
+
+func Test_Downsample(t *testing.T) {
+	db, err := gorm.Open(&Dialect{DSN: dsnWithDb}, &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	minute := window.Duration{Value: 1, Unit: window.Minute}
+	hour := window.Duration{Value: 1, Unit: window.Hour}
+
+	inner := db.Table("meters").
+		Select("_wstart AS `ts`,avg(`current`) AS `v`").
+		Clauses(partition.PartitionBy{}.TbName(), window.SetInterval(minute))
+	stmt := Downsample(db, inner, hour).
+		Select("_wstart,avg(`v`)").
+		Clauses(fill.Fill{Type: fill.FillNull}).
+		Find(&[]map[string]any{}).Statement
+	if stmt.Error != nil {
+		t.Fatalf("unexpected error: %v", stmt.Error)
+	}
+	want := "SELECT _wstart,avg(`v`) FROM (SELECT _wstart AS `ts`,avg(`current`) AS `v` FROM `meters` PARTITION BY tbname INTERVAL(1m)) AS `t` INTERVAL(1h) FILL (NULL)"
+	if got := strings.TrimSpace(stmt.SQL.String()); got != want {
+		t.Errorf("expect sql: %s, got: %s", want, got)
+	}
+
+	err = Downsample(db, db.Table("meters").Select("`ts`,`current`"), hour).Find(&[]map[string]any{}).Error
+	if err == nil {
+		t.Errorf("expect error for inner query without window")
+	}
+}
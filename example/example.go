@@ -22,6 +22,13 @@ type Data struct {
 	Value int64
 }
 
+// Bucket window result, window pseudo columns scan into read-only fields.
+type Bucket struct {
+	Start time.Time `gorm:"column:_wstart;->"`
+	End   time.Time `gorm:"column:_wend;->"`
+	V     sql.NullInt64
+}
+
 func main() {
 	//create database
 	createDatabase()
@@ -93,32 +100,25 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
-	//SELECT max(`value`) as v FROM tb_aggregate WHERE ts >= '2021-08-11 09:43:01.041' and ts <= '2021-08-11 09:43:04.041' INTERVAL(1000000u) FILL (NULL)
-	resultWindowMax := aggregateQuery(db, "tb_aggregate", "max(`value`) as v", t1, t4, []clause.Expression{
+	//SELECT _wstart AS `_wstart`,_wend AS `_wend`,max(`value`) as v FROM tb_aggregate WHERE ts >= '2021-08-11 09:43:01.041' and ts <= '2021-08-11 09:43:04.041' INTERVAL(1s) FILL (NULL)
+	resultWindowMax := windowQuery(db, "tb_aggregate", "max(`value`) as v", t1, t4, []clause.Expression{
 		window.SetInterval(*windowD),
 		fill.Fill{Type: fill.FillNull},
 	})
-	expectWindowMax := []map[string]any{
-		{
-			"ts": now.Add(time.Second),
-			"v":  int64(11),
-		},
-		{
-			"ts": now.Add(time.Second * 2),
-			"v":  int64(12),
-		},
-		{
-			"ts": now.Add(time.Second * 3),
-			"v":  int64(13),
-		},
-		{
-			"ts": now.Add(time.Second * 4),
-			"v":  nil,
-		},
+	expectWindowMax := []sql.NullInt64{
+		{Int64: 11, Valid: true},
+		{Int64: 12, Valid: true},
+		{Int64: 13, Valid: true},
+		{},
 	}
-	if !resultMapEqual(expectWindowMax, resultWindowMax) {
+	if len(resultWindowMax) != len(expectWindowMax) {
 		log.Fatalf("expect %v got %v", expectWindowMax, resultWindowMax)
 	}
+	for i, b := range resultWindowMax {
+		if b.V != expectWindowMax[i] || !b.End.After(b.Start) {
+			log.Fatalf("expect %v got %v", expectWindowMax[i], b)
+		}
+	}
 }
 
 func createDatabase() {
@@ -238,6 +238,19 @@ func aggregateQuery(db *gorm.DB, tableName string, query string, start, end time
 	return result
 }
 
+func windowQuery(db *gorm.DB, tableName string, query string, start, end time.Time, conds []clause.Expression) []Bucket {
+	var result []Bucket
+	err := db.Table(tableName).
+		Select("?,?,"+query, window.WStart.Column(), window.WEnd.Column()).
+		Where("`ts` >= ? and `ts` <= ?", start, end).
+		Clauses(conds...).
+		Find(&result).Error
+	if err != nil {
+		log.Fatalf("window query error %v", err)
+	}
+	return result
+}
+
 func resultMapEqual(m1, m2 []map[string]any) bool {
 	if len(m1) != len(m2) {
 		return false
@@ -0,0 +1,91 @@
+package tdengine_gorm
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"sync/atomic"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// fakeRowsDriver driver which returns the fixed rows for any query, or the rows of query if set.
+type fakeRowsDriver struct {
+	columns []string
+	values  [][]driver.Value
+	query   func(query string) ([]string, [][]driver.Value, error)
+	closed  atomic.Int32
+}
+
+func (d *fakeRowsDriver) Open(string) (driver.Conn, error) { return &fakeConn{d: d}, nil }
+
+// fakeConnector connector of the fake driver, so that it is opened without sql.Register.
+type fakeConnector struct{ d *fakeRowsDriver }
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) { return &fakeConn{d: c.d}, nil }
+func (c fakeConnector) Driver() driver.Driver                        { return c.d }
+
+// openFakeDB open the dialect on the fake driver, dsn provides the default database.
+func openFakeDB(t *testing.T, d *fakeRowsDriver, dsn string) *gorm.DB {
+	t.Helper()
+	return openFakeDialect(t, d, Dialect{DSN: dsn})
+}
+
+// openFakeDialect open the dialect with the options on the fake driver.
+func openFakeDialect(t *testing.T, d *fakeRowsDriver, dialect Dialect) *gorm.DB {
+	t.Helper()
+	dialect.Conn = sql.OpenDB(fakeConnector{d: d})
+	db, err := gorm.Open(&dialect, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return db
+}
+
+type fakeConn struct{ d *fakeRowsDriver }
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)           { return nil, driver.ErrSkip }
+func (c *fakeConn) Query(query string, _ []driver.Value) (driver.Rows, error) {
+	if c.d.query == nil {
+		return &fakeRows{d: c.d, columns: c.d.columns, values: c.d.values}, nil
+	}
+	columns, values, err := c.d.query(query)
+	if err != nil {
+		return nil, err
+	}
+	return &fakeRows{d: c.d, columns: columns, values: values}, nil
+}
+
+func (c *fakeConn) Exec(query string, _ []driver.Value) (driver.Result, error) {
+	if c.d.query != nil {
+		if _, _, err := c.d.query(query); err != nil {
+			return nil, err
+		}
+	}
+	return driver.RowsAffected(0), nil
+}
+
+type fakeRows struct {
+	d       *fakeRowsDriver
+	columns []string
+	values  [][]driver.Value
+	i       int
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error {
+	r.d.closed.Add(1)
+	return nil
+}
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.i >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.i])
+	r.i++
+	return nil
+}
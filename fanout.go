@@ -0,0 +1,101 @@
+package tdengine_gorm
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TableError error of the query against a child table.
+type TableError struct {
+	Table string
+	Err   error
+}
+
+func (e *TableError) Error() string {
+	return "tdengine: table " + e.Table + ": " + e.Err.Error()
+}
+
+func (e *TableError) Unwrap() error {
+	return e.Err
+}
+
+// FanOut run the query template against each child table of the super table with bounded concurrency, and merge the rows.
+// db carries the super table model and the tag filters which select the child tables,
+// query builds the query of the child table on tx, which is bound to the child table and ctx.
+//
+// The rows of the succeeded tables are returned in the order of the tables even if some tables fail,
+// the error joins a *TableError of each failed table. Use a ctx with deadline as the global deadline,
+// tables not queried before the deadline fail with the context error.
+// concurrency should not exceed the open connections of the pool.
+//
+//	rows, err := FanOut[Stat](ctx, db.Model(&Meter{}).Where(Tag("location").Eq("bj")), 8, func(tx *gorm.DB, table string) *gorm.DB {
+//		return tx.Select("? AS `tbname`,`ts`,`current`", table).Scopes(TimeRange("ts", from, to))
+//	})
+func FanOut[T any](ctx context.Context, db *gorm.DB, concurrency int, query func(tx *gorm.DB, table string) *gorm.DB) ([]T, error) {
+	tables, err := childTables(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	return fanOut[T](ctx, db, tables, concurrency, query)
+}
+
+// childTables list the child tables of the super table which match the filters [SELECT DISTINCT tbname FROM stb WHERE filters]
+// the tables are qualified with the database of the super table if any.
+func childTables(ctx context.Context, db *gorm.DB) ([]string, error) {
+	var tables []string
+	tx := db.WithContext(ctx).Clauses(clause.Select{Distinct: true, Expression: clause.Expr{SQL: "?", Vars: []any{TbName()}}}).Find(&tables)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	if database := databaseOf(tx.Statement); database != "" {
+		for i, table := range tables {
+			tables[i] = database + "." + table
+		}
+	}
+	return tables, nil
+}
+
+func fanOut[T any](ctx context.Context, db *gorm.DB, tables []string, concurrency int, query func(tx *gorm.DB, table string) *gorm.DB) ([]T, error) {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	var (
+		results = make([][]T, len(tables))
+		errs    = make([]error, len(tables))
+		sem     = make(chan struct{}, concurrency)
+		wg      sync.WaitGroup
+	)
+	for i, table := range tables {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = &TableError{Table: table, Err: ctx.Err()}
+			continue
+		}
+		wg.Add(1)
+		go func(i int, table string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			var rows []T
+			tx := db.Session(&gorm.Session{NewDB: true}).WithContext(ctx).Table(table)
+			if err := query(tx, table).Find(&rows).Error; err != nil {
+				errs[i] = &TableError{Table: table, Err: err}
+				return
+			}
+			results[i] = rows
+		}(i, table)
+	}
+	wg.Wait()
+
+	var merged []T
+	for _, rows := range results {
+		merged = append(merged, rows...)
+	}
+	return merged, errors.Join(errs...)
+}
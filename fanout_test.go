@@ -0,0 +1,70 @@
+package tdengine_gorm
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type fanOutRow struct {
+	TbName string `gorm:"column:tbname"`
+	V      float64
+}
+
+func Test_FanOut(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		queries []string
+	)
+	fake := &fakeRowsDriver{
+		query: func(query string) ([]string, [][]driver.Value, error) {
+			mu.Lock()
+			queries = append(queries, query)
+			mu.Unlock()
+			switch {
+			case strings.HasPrefix(query, "SELECT DISTINCT tbname FROM `meters`"):
+				return []string{"tbname"}, [][]driver.Value{{"d1"}, {"d2"}, {"d3"}}, nil
+			case strings.Contains(query, "`d2`"):
+				return nil, nil, errors.New("table d2 offline")
+			case strings.Contains(query, "`d1`"):
+				return []string{"tbname", "v"}, [][]driver.Value{{"d1", 1.0}, {"d1", 2.0}}, nil
+			default:
+				return []string{"tbname", "v"}, [][]driver.Value{{"d3", 3.0}}, nil
+			}
+		},
+	}
+	db := openFakeDB(t, fake, "")
+	ctx := context.Background()
+
+	rows, err := FanOut[fanOutRow](ctx, db.Model(&tagMeter{}).Where(Tag("location").Eq("bj")), 2, func(tx *gorm.DB, table string) *gorm.DB {
+		return tx.Select("? AS `tbname`,avg(`current`) AS `v`", table)
+	})
+	if queries[0] != "SELECT DISTINCT tbname FROM `meters` WHERE `location` = ?" {
+		t.Errorf("unexpected child tables query: %s", queries[0])
+	}
+	var tableErr *TableError
+	if !errors.As(err, &tableErr) || tableErr.Table != "d2" {
+		t.Fatalf("expect error of table d2, got: %v", err)
+	}
+	want := []fanOutRow{{"d1", 1}, {"d1", 2}, {"d3", 3}}
+	if len(rows) != len(want) {
+		t.Fatalf("expect partial rows %v, got: %v", want, rows)
+	}
+	for i := range want {
+		if rows[i] != want[i] {
+			t.Errorf("expect partial rows %v, got: %v", want, rows)
+		}
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = fanOut[fanOutRow](cancelled, db, []string{"d1", "d3"}, 1, func(tx *gorm.DB, table string) *gorm.DB { return tx })
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expect context canceled error, got: %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+package tdengine_gorm
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// GroupRows group the scanned rows by the tbname or the tag column into map[K][]T,
+// rows of each group are kept in the scanned order, e.g. the rows of [PARTITION BY tbname] or [GROUP BY tag] with ORDER BY ts DESC.
+//
+//	var rows []Meter
+//	db.Clauses(partition.SetPartitionBy().TbName()).Find(&rows)
+//	byDevice, err := GroupRows[string](db, rows, "tbname")
+func GroupRows[K comparable, T any](db *gorm.DB, rows []T, column string) (map[K][]T, error) {
+	s, err := parseRow[T](db)
+	if err != nil {
+		return nil, err
+	}
+	keyField := lookUpTag(s, column)
+	if keyField == nil {
+		keyField = s.LookUpField(column)
+	}
+	if keyField == nil {
+		return nil, fmt.Errorf("tdengine: column %q not found in %s", column, s.Name)
+	}
+
+	ctx := context.Background()
+	groups := make(map[K][]T)
+	for _, row := range rows {
+		v, _ := keyField.ValueOf(ctx, reflect.ValueOf(row))
+		key, ok := v.(K)
+		if !ok {
+			return nil, fmt.Errorf("tdengine: column %q of %s is %T, not %T", column, s.Name, v, *new(K))
+		}
+		groups[key] = append(groups[key], row)
+	}
+	return groups, nil
+}
+
+// WideRow row of the wide format, values are in the order of Wide.Keys, nil if the group has no row at the timestamp.
+type WideRow[V any] struct {
+	TS     time.Time
+	Values []*V
+}
+
+// Wide the wide format of the grouped rows, one column per group with aligned timestamps.
+type Wide[K comparable, V any] struct {
+	Keys []K
+	Rows []WideRow[V]
+}
+
+// Pivot pivot the grouped rows into the wide format with the value column,
+// keys are sorted, rows are the union of the timestamps of all groups in ascending order.
+//
+//	wide, err := Pivot[string, Meter, float64](db, byDevice, "current")
+func Pivot[K cmp.Ordered, T any, V any](db *gorm.DB, groups map[K][]T, column string) (*Wide[K, V], error) {
+	s, err := parseRow[T](db)
+	if err != nil {
+		return nil, err
+	}
+	tsField := timestampField(s)
+	if tsField == nil {
+		return nil, fmt.Errorf("tdengine: timestamp field not found in %s", s.Name)
+	}
+	valueField := s.LookUpField(column)
+	if valueField == nil {
+		return nil, fmt.Errorf("tdengine: column %q not found in %s", column, s.Name)
+	}
+
+	ctx := context.Background()
+	wide := &Wide[K, V]{Keys: make([]K, 0, len(groups))}
+	for key := range groups {
+		wide.Keys = append(wide.Keys, key)
+	}
+	slices.Sort(wide.Keys)
+
+	// timestamps are keyed in UTC, which drops the location and the monotonic clock reading.
+	seen := make(map[time.Time]struct{})
+	var stamps []time.Time
+	for _, key := range wide.Keys {
+		for _, row := range groups[key] {
+			ts := rowTime(ctx, tsField, row)
+			if _, ok := seen[ts.UTC()]; !ok {
+				seen[ts.UTC()] = struct{}{}
+				stamps = append(stamps, ts)
+			}
+		}
+	}
+	slices.SortFunc(stamps, func(a, b time.Time) int { return a.Compare(b) })
+	index := make(map[time.Time]int, len(stamps))
+	wide.Rows = make([]WideRow[V], len(stamps))
+	for i, ts := range stamps {
+		index[ts.UTC()] = i
+		wide.Rows[i] = WideRow[V]{TS: ts, Values: make([]*V, len(wide.Keys))}
+	}
+	for k, key := range wide.Keys {
+		for _, row := range groups[key] {
+			v, _ := valueField.ValueOf(ctx, reflect.ValueOf(row))
+			value, err := valueOf[V](v)
+			if err != nil {
+				return nil, fmt.Errorf("tdengine: column %q of %s: %w", column, s.Name, err)
+			}
+			wide.Rows[index[rowTime(ctx, tsField, row).UTC()]].Values[k] = value
+		}
+	}
+	return wide, nil
+}
+
+func parseRow[T any](db *gorm.DB) (*schema.Schema, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return nil, err
+	}
+	return stmt.Schema, nil
+}
+
+// timestampField the first time.Time field, which is the timestamp column of TDengine table.
+func timestampField(s *schema.Schema) *schema.Field {
+	for _, field := range s.Fields {
+		if field.DBName != "" && field.IndirectFieldType == reflect.TypeOf(time.Time{}) {
+			return field
+		}
+	}
+	return nil
+}
+
+func rowTime(ctx context.Context, field *schema.Field, row any) time.Time {
+	switch v, _ := field.ValueOf(ctx, reflect.ValueOf(row)); v := v.(type) {
+	case time.Time:
+		return v
+	case *time.Time:
+		if v != nil {
+			return *v
+		}
+	}
+	return time.Time{}
+}
+
+// valueOf the value of V or *V, nil for nil pointer.
+func valueOf[V any](v any) (*V, error) {
+	switch v := v.(type) {
+	case V:
+		return &v, nil
+	case *V:
+		return v, nil
+	}
+	return nil, fmt.Errorf("%T is not %T", v, *new(V))
+}
@@ -0,0 +1,87 @@
+package tdengine_gorm
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type groupMeter struct {
+	TbName   string `gorm:"column:tbname;->"`
+	TS       time.Time
+	Current  *float64
+	Location string `gorm:"->" tdengine:"tag"`
+	GroupID  int    `gorm:"->" tdengine:"tag"`
+}
+
+func Test_GroupRows(t *testing.T) {
+	db, err := gorm.Open(&Dialect{DSN: dsnWithDb}, &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	f := func(v float64) *float64 { return &v }
+	rows := []groupMeter{
+		{TbName: "d2", TS: t0.Add(time.Minute).In(time.FixedZone("CST", 8*3600)), Current: f(21), GroupID: 2},
+		{TbName: "d1", TS: t0.Add(time.Minute), Current: f(11), GroupID: 1},
+		{TbName: "d1", TS: t0, Current: f(10), GroupID: 1},
+		{TbName: "d2", TS: t0.Add(2 * time.Minute), Current: nil, GroupID: 2},
+	}
+
+	byTable, err := GroupRows[string](db, rows, "tbname")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(byTable) != 2 || len(byTable["d1"]) != 2 || !byTable["d1"][0].TS.Equal(t0.Add(time.Minute)) {
+		t.Errorf("unexpected groups: %v", byTable)
+	}
+	byTag, err := GroupRows[int](db, rows, "GroupID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(byTag[2]) != 2 || byTag[2][0].TbName != "d2" {
+		t.Errorf("unexpected groups: %v", byTag)
+	}
+	if _, err = GroupRows[int](db, rows, "tbname"); err == nil {
+		t.Errorf("expect key type error")
+	}
+	if _, err = GroupRows[string](db, rows, "unknown"); err == nil {
+		t.Errorf("expect column not found error")
+	}
+
+	wide, err := Pivot[string, groupMeter, float64](db, byTable, "current")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(wide.Keys) != 2 || wide.Keys[0] != "d1" || len(wide.Rows) != 3 {
+		t.Fatalf("unexpected wide: %+v", wide)
+	}
+	want := [][]*float64{{f(10), nil}, {f(11), f(21)}, {nil, nil}}
+	for i, row := range wide.Rows {
+		if !row.TS.Equal(t0.Add(time.Duration(i) * time.Minute)) {
+			t.Errorf("row %d: unexpected timestamp %v", i, row.TS)
+		}
+		for k, v := range row.Values {
+			if (v == nil) != (want[i][k] == nil) || (v != nil && *v != *want[i][k]) {
+				t.Errorf("row %d: unexpected values %v", i, row.Values)
+			}
+		}
+	}
+	if _, err = Pivot[string, groupMeter, string](db, byTable, "current"); err == nil {
+		t.Errorf("expect value type error")
+	}
+	// the UnixNano of the two timestamps overflows to the same value.
+	far := map[string][]groupMeter{
+		"d1": {{TS: t0, Current: f(1)}},
+		"d2": {{TS: t0.Add(math.MaxInt64).Add(1), Current: f(2)}},
+	}
+	wide, err = Pivot[string, groupMeter, float64](db, far, "current")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(wide.Rows) != 2 {
+		t.Errorf("unexpected wide: %+v", wide)
+	}
+}
@@ -0,0 +1,278 @@
+package tdengine_gorm
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+
+	"github.com/thinkgos/tdengine-gorm/clause/hint"
+	"github.com/thinkgos/tdengine-gorm/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ErrUnsupported is returned when a statement relies on a gorm feature
+// which TDengine can not execute.
+type ErrUnsupported struct {
+	Feature string
+}
+
+func (e ErrUnsupported) Error() string {
+	return "tdengine: " + e.Feature + " not supported"
+}
+
+var deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+
+// registerGuards register callbacks which reject unsupported features before the statement is executed.
+func registerGuards(db *gorm.DB) error {
+	if err := db.Callback().Create().Before("*").Register("tdengine:guard", guardCreate); err != nil {
+		return err
+	}
+	if err := db.Callback().Query().Before("gorm:query").Register("tdengine:guard", guardQuery); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().Before("gorm:row").Register("tdengine:guard", guardQuery); err != nil {
+		return err
+	}
+	if err := db.Callback().Query().Before("gorm:query").Register("tdengine:subquery", guardSubqueries); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().Before("gorm:row").Register("tdengine:subquery", guardSubqueries); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("*").Register("tdengine:guard", guardUpdate); err != nil {
+		return err
+	}
+	return db.Callback().Delete().Before("*").Register("tdengine:guard", guardDelete)
+}
+
+func guardCreate(db *gorm.DB) {
+	stmt := db.Statement
+	if hasHints(stmt) {
+		_ = db.AddError(ErrUnsupported{Feature: "hints in non-query statement"})
+		return
+	}
+	if _, ok := stmt.Clauses["RETURNING"]; ok {
+		_ = db.AddError(ErrUnsupported{Feature: "RETURNING"})
+		return
+	}
+	if hasAssociations(stmt, true) {
+		_ = db.AddError(ErrUnsupported{Feature: "associations"})
+	}
+}
+
+func guardQuery(db *gorm.DB) {
+	stmt := db.Statement
+	if len(stmt.Preloads) > 0 {
+		_ = db.AddError(ErrUnsupported{Feature: "preload"})
+		return
+	}
+	for _, j := range stmt.Joins {
+		if j.On != nil {
+			_ = db.AddError(ErrUnsupported{Feature: "join with ON"})
+			return
+		}
+		if stmt.Schema != nil && stmt.Schema.Relationships.Relations[j.Name] != nil {
+			_ = db.AddError(ErrUnsupported{Feature: "association join"})
+			return
+		}
+	}
+	if hasSoftDelete(stmt) {
+		_ = db.AddError(ErrUnsupported{Feature: "soft delete"})
+	}
+}
+
+// guardSubqueries pass the errors of the subqueries on, e.g. a rejected feature or precision of the subquery.
+func guardSubqueries(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+	for _, query := range subqueries(db.Statement) {
+		if err := utils.SubqueryError(query); err != nil {
+			_ = db.AddError(err)
+			return
+		}
+	}
+}
+
+// subqueries the *gorm.DB vars of the table expression and the clauses, which are built as subqueries.
+func subqueries(stmt *gorm.Statement) []*gorm.DB {
+	var queries []*gorm.DB
+	var walk func(v reflect.Value)
+	walk = func(v reflect.Value) {
+		switch v.Kind() {
+		case reflect.Interface:
+			if !v.IsNil() {
+				walk(v.Elem())
+			}
+		case reflect.Pointer:
+			if v.IsNil() {
+				return
+			}
+			if query, ok := v.Interface().(*gorm.DB); ok {
+				queries = append(queries, query)
+				return
+			}
+			if v.Type().Implements(expressionType) {
+				walk(v.Elem())
+			}
+		case reflect.Slice, reflect.Array:
+			switch v.Type().Elem().Kind() {
+			case reflect.Interface, reflect.Pointer, reflect.Struct, reflect.Slice:
+				for i := 0; i < v.Len(); i++ {
+					walk(v.Index(i))
+				}
+			}
+		case reflect.Struct:
+			// only the exported fields of the expressions, user values are not walked.
+			if !v.Type().Implements(expressionType) && v.Type().PkgPath() != clausePkgPath {
+				return
+			}
+			for i := 0; i < v.NumField(); i++ {
+				if v.Type().Field(i).IsExported() {
+					walk(v.Field(i))
+				}
+			}
+		}
+	}
+	if stmt.TableExpr != nil {
+		walk(reflect.ValueOf(*stmt.TableExpr))
+	}
+	for _, c := range stmt.Clauses {
+		walk(reflect.ValueOf(c.Expression))
+	}
+	return queries
+}
+
+var (
+	expressionType = reflect.TypeOf((*clause.Expression)(nil)).Elem()
+	clausePkgPath  = reflect.TypeOf(clause.Expr{}).PkgPath()
+)
+
+func guardUpdate(db *gorm.DB) {
+	stmt := db.Statement
+	if hasHints(stmt) {
+		_ = db.AddError(ErrUnsupported{Feature: "hints in non-query statement"})
+		return
+	}
+	if hasSoftDelete(stmt) {
+		_ = db.AddError(ErrUnsupported{Feature: "soft delete"})
+		return
+	}
+	if hasAssociations(stmt, false) {
+		_ = db.AddError(ErrUnsupported{Feature: "associations"})
+	}
+}
+
+func guardDelete(db *gorm.DB) {
+	stmt := db.Statement
+	if hasHints(stmt) {
+		_ = db.AddError(ErrUnsupported{Feature: "hints in non-query statement"})
+		return
+	}
+	if hasSoftDelete(stmt) {
+		_ = db.AddError(ErrUnsupported{Feature: "soft delete"})
+		return
+	}
+	for _, s := range stmt.Selects {
+		if s == clause.Associations {
+			_ = db.AddError(ErrUnsupported{Feature: "associations"})
+			return
+		}
+	}
+}
+
+// hasHints report whether the statement has query hints.
+func hasHints(stmt *gorm.Statement) bool {
+	_, ok := stmt.Clauses["SELECT"].AfterNameExpression.(hint.Hints)
+	return ok
+}
+
+// hasSoftDelete report whether the model has a gorm.DeletedAt field, which is in effect for scoped statement.
+func hasSoftDelete(stmt *gorm.Statement) bool {
+	if stmt.Schema == nil || stmt.Unscoped {
+		return false
+	}
+	for _, field := range stmt.Schema.Fields {
+		if field.FieldType == deletedAtType {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAssociations report whether the statement will save any non-zero association.
+func hasAssociations(stmt *gorm.Statement, create bool) bool {
+	if stmt.Schema == nil || len(stmt.Schema.Relationships.Relations) == 0 {
+		return false
+	}
+	if !stmt.ReflectValue.IsValid() {
+		return false
+	}
+	selectColumns, restricted := stmt.SelectAndOmitColumns(create, !create)
+	for name, rel := range stmt.Schema.Relationships.Relations {
+		if v, ok := selectColumns[name]; (ok && !v) || (!ok && restricted) {
+			continue
+		}
+		if isRelationSet(stmt.Context, rel, stmt.ReflectValue) {
+			return true
+		}
+	}
+	return false
+}
+
+func isRelationSet(ctx context.Context, rel *schema.Relationship, rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if isRelationSet(ctx, rel, reflect.Indirect(rv.Index(i))) {
+				return true
+			}
+		}
+	case reflect.Struct:
+		if _, zero := rel.Field.ValueOf(ctx, rv); !zero {
+			return true
+		}
+	}
+	return false
+}
+
+// connPool wrap the connection pool so that gorm transactions are either rejected or run as no-op.
+type connPool struct {
+	gorm.ConnPool
+	noopTransaction bool
+}
+
+var _ gorm.ConnPoolBeginner = (*connPool)(nil)
+var _ gorm.GetDBConnector = (*connPool)(nil)
+
+func (p *connPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	if !p.noopTransaction {
+		return nil, ErrUnsupported{Feature: "transaction"}
+	}
+	return &noopTx{ConnPool: p.ConnPool}, nil
+}
+
+func (p *connPool) GetDBConn() (*sql.DB, error) {
+	if sqlDB, ok := p.ConnPool.(*sql.DB); ok {
+		return sqlDB, nil
+	}
+	if connector, ok := p.ConnPool.(gorm.GetDBConnector); ok {
+		return connector.GetDBConn()
+	}
+	return nil, gorm.ErrInvalidDB
+}
+
+// noopTx run statements directly on the connection pool, commit and rollback do nothing.
+type noopTx struct {
+	gorm.ConnPool
+}
+
+var _ gorm.Tx = (*noopTx)(nil)
+
+func (*noopTx) Commit() error   { return nil }
+func (*noopTx) Rollback() error { return nil }
+
+// StmtContext the statement prepared on the connection pool is used as is, required by PrepareStmt.
+func (*noopTx) StmtContext(_ context.Context, stmt *sql.Stmt) *sql.Stmt { return stmt }
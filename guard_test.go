@@ -0,0 +1,165 @@
+package tdengine_gorm
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/thinkgos/tdengine-gorm/clause/hint"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type guardDevice struct {
+	ID   int64
+	Name string
+}
+
+type guardReading struct {
+	TS       time.Time
+	Value    float64
+	DeviceID int64
+	Device   guardDevice
+}
+
+type guardSoftDelete struct {
+	TS        time.Time
+	Value     float64
+	DeletedAt gorm.DeletedAt
+}
+
+func Test_Guard(t *testing.T) {
+	db, err := gorm.Open(&Dialect{DSN: dsnWithDb}, &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		name    string
+		run     func(tx *gorm.DB) error
+		feature string
+	}{
+		{
+			name:    "preload",
+			run:     func(tx *gorm.DB) error { return tx.Preload("Device").Find(&[]guardReading{}).Error },
+			feature: "preload",
+		},
+		{
+			name:    "association join",
+			run:     func(tx *gorm.DB) error { return tx.Joins("Device").Find(&[]guardReading{}).Error },
+			feature: "association join",
+		},
+		{
+			name:    "soft delete query",
+			run:     func(tx *gorm.DB) error { return tx.Find(&[]guardSoftDelete{}).Error },
+			feature: "soft delete",
+		},
+		{
+			name: "soft delete subquery",
+			run: func(tx *gorm.DB) error {
+				return tx.Table("(?) AS `t`", tx.Model(&guardSoftDelete{}).Select("ts")).Find(&[]map[string]any{}).Error
+			},
+			feature: "soft delete",
+		},
+		{
+			name: "soft delete subquery in where",
+			run: func(tx *gorm.DB) error {
+				return tx.Table("meters").Where("`ts` IN (?)", tx.Model(&guardSoftDelete{}).Select("ts")).Find(&[]map[string]any{}).Error
+			},
+			feature: "soft delete",
+		},
+		{
+			name:    "soft delete",
+			run:     func(tx *gorm.DB) error { return tx.Where("`ts` < ?", time.Now()).Delete(&guardSoftDelete{}).Error },
+			feature: "soft delete",
+		},
+		{
+			name: "returning",
+			run: func(tx *gorm.DB) error {
+				return tx.Clauses(clause.Returning{}).Create(&guardSoftDelete{TS: time.Now()}).Error
+			},
+			feature: "RETURNING",
+		},
+		{
+			name: "associations",
+			run: func(tx *gorm.DB) error {
+				return tx.Create(&guardReading{TS: time.Now(), Device: guardDevice{ID: 1, Name: "d1"}}).Error
+			},
+			feature: "associations",
+		},
+		{
+			name: "transaction",
+			run: func(tx *gorm.DB) error {
+				return tx.Transaction(func(tx *gorm.DB) error { return nil })
+			},
+			feature: "transaction",
+		},
+		{
+			name: "hints",
+			run: func(tx *gorm.DB) error {
+				return tx.Clauses(hint.New(hint.ParaTablesSort)).Create(&guardReading{TS: time.Now()}).Error
+			},
+			feature: "hints in non-query statement",
+		},
+		{
+			name:    "savepoint",
+			run:     func(tx *gorm.DB) error { return tx.SavePoint("sp").Error },
+			feature: "savepoint",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.run(db.Session(&gorm.Session{}))
+			var e ErrUnsupported
+			if !errors.As(err, &e) {
+				t.Fatalf("expect ErrUnsupported, got: %v", err)
+			}
+			if e.Feature != tc.feature {
+				t.Errorf("expect feature %q, got %q", tc.feature, e.Feature)
+			}
+		})
+	}
+
+	t.Run("allowed", func(t *testing.T) {
+		tx := db.Session(&gorm.Session{})
+		if err := tx.Unscoped().Find(&[]guardSoftDelete{}).Error; err != nil {
+			t.Errorf("unscoped query: unexpected error: %v", err)
+		}
+		if err := tx.Table("(?) AS `t`", tx.Unscoped().Model(&guardSoftDelete{}).Select("ts")).Find(&[]map[string]any{}).Error; err != nil {
+			t.Errorf("unscoped subquery: unexpected error: %v", err)
+		}
+		if err := tx.Create(&guardReading{TS: time.Now(), Value: 1}).Error; err != nil {
+			t.Errorf("create without associations: unexpected error: %v", err)
+		}
+		if err := tx.Omit(clause.Associations).Create(&guardReading{TS: time.Now(), Device: guardDevice{ID: 1}}).Error; err != nil {
+			t.Errorf("create omit associations: unexpected error: %v", err)
+		}
+	})
+
+	t.Run("noop transaction", func(t *testing.T) {
+		db, err := gorm.Open(&Dialect{DSN: dsnWithDb, NoopTransaction: true}, &gorm.Config{DryRun: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		err = db.Transaction(func(tx *gorm.DB) error {
+			return tx.Create(&guardReading{TS: time.Now(), Value: 1}).Error
+		})
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if _, err = db.DB(); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		db, err = gorm.Open(&Dialect{DSN: dsnWithDb, NoopTransaction: true}, &gorm.Config{DryRun: true, PrepareStmt: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		err = db.Transaction(func(tx *gorm.DB) error {
+			return tx.Create(&guardReading{TS: time.Now(), Value: 1}).Error
+		})
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package tdengine_gorm
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// JSON value of the json tag, T can be map[string]any or a user struct.
+// It is marshaled to a json string when inserting, e.g. through USING, and unmarshaled when scanning.
+//
+//	type Device struct {
+//		TS    time.Time
+//		Value float64
+//		Info  JSON[map[string]any] `gorm:"->" tdengine:"tag"`
+//	}
+type JSON[T any] struct {
+	Data T
+}
+
+// NewJSON new json tag value.
+func NewJSON[T any](data T) JSON[T] {
+	return JSON[T]{Data: data}
+}
+
+// Value implement driver.Valuer
+func (j JSON[T]) Value() (driver.Value, error) {
+	b, err := json.Marshal(j.Data)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implement sql.Scanner
+func (j *JSON[T]) Scan(src any) error {
+	var b []byte
+	switch v := src.(type) {
+	case nil:
+		var zero T
+		j.Data = zero
+		return nil
+	case string:
+		b = []byte(v)
+	case []byte:
+		b = v
+	default:
+		return fmt.Errorf("tdengine: can not scan %T into JSON", src)
+	}
+	if len(b) == 0 || string(b) == "null" {
+		var zero T
+		j.Data = zero
+		return nil
+	}
+	return json.Unmarshal(b, &j.Data)
+}
+
+// GormDataType gorm data type
+func (JSON[T]) GormDataType() string {
+	return "JSON"
+}
@@ -0,0 +1,43 @@
+package tdengine_gorm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_JSON(t *testing.T) {
+	type info struct {
+		Model string `json:"model"`
+		Rack  int    `json:"rack"`
+	}
+
+	v, err := NewJSON(info{Model: "m1", Rack: 3}).Value()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v != `{"model":"m1","rack":3}` {
+		t.Errorf("unexpected value: %v", v)
+	}
+
+	var got JSON[info]
+	if err = got.Scan([]byte(`{"model":"m2","rack":4}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Data != (info{Model: "m2", Rack: 4}) {
+		t.Errorf("unexpected data: %v", got.Data)
+	}
+
+	var m JSON[map[string]any]
+	if err = m.Scan(`{"k":"v"}`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(m.Data, map[string]any{"k": "v"}) {
+		t.Errorf("unexpected data: %v", m.Data)
+	}
+	if err = m.Scan(nil); err != nil || m.Data != nil {
+		t.Errorf("expect nil data, got: %v, %v", m.Data, err)
+	}
+	if err = m.Scan(1); err == nil {
+		t.Errorf("expect error")
+	}
+}
@@ -0,0 +1,198 @@
+package tdengine_gorm
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Cursor position of the keyset pagination, the timestamp and the tbname of the last row.
+type Cursor struct {
+	TS     time.Time `json:"ts"`
+	TbName string    `json:"tb,omitempty"`
+}
+
+// Token encode the cursor to an opaque token, it can be decoded by ParseCursor.
+func (c Cursor) Token() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// ParseCursor decode the token of Cursor.Token.
+func ParseCursor(token string) (Cursor, error) {
+	var c Cursor
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, fmt.Errorf("tdengine: invalid cursor token: %w", err)
+	}
+	if err = json.Unmarshal(b, &c); err != nil {
+		return c, fmt.Errorf("tdengine: invalid cursor token: %w", err)
+	}
+	return c, nil
+}
+
+// Keyset keyset pagination by timestamp, pages by [ts > last_ts ORDER BY ts LIMIT size] instead of OFFSET.
+// Rows of a super table may share the timestamp, ByTbName pages by (ts, tbname),
+// T must have a tbname field then, e.g. `gorm:"column:tbname;->"`.
+//
+//	pager := NewKeyset[Meter](db.Where(Tag("location").Eq("bj")), 10000).ByTbName()
+//	for {
+//		rows, err := pager.Next(ctx)
+//		if err != nil || len(rows) == 0 {
+//			break
+//		}
+//		// save pager.Cursor().Token() to resume later
+//	}
+type Keyset[T any] struct {
+	db     *gorm.DB
+	size   int
+	column string
+	desc   bool
+	tbName bool
+	cursor *Cursor
+	done   bool
+}
+
+// NewKeyset create a keyset pagination of T by the `ts` column in ascending order.
+func NewKeyset[T any](db *gorm.DB, size int) *Keyset[T] {
+	return &Keyset[T]{db: db, size: size, column: "ts"}
+}
+
+// Column set the timestamp column, default `ts`.
+func (k *Keyset[T]) Column(column string) *Keyset[T] {
+	k.column = column
+	return k
+}
+
+// Desc page in descending order.
+func (k *Keyset[T]) Desc() *Keyset[T] {
+	k.desc = true
+	return k
+}
+
+// ByTbName page by (ts, tbname), for super table.
+func (k *Keyset[T]) ByTbName() *Keyset[T] {
+	k.tbName = true
+	return k
+}
+
+// Resume continue after the cursor of the token.
+func (k *Keyset[T]) Resume(token string) error {
+	c, err := ParseCursor(token)
+	if err != nil {
+		return err
+	}
+	k.cursor = &c
+	k.done = false
+	return nil
+}
+
+// Cursor the cursor of the last row returned, nil before the first row.
+func (k *Keyset[T]) Cursor() *Cursor {
+	return k.cursor
+}
+
+// Done report whether all rows are returned.
+func (k *Keyset[T]) Done() bool {
+	return k.done
+}
+
+// Next the next page, an empty page when all rows are returned.
+func (k *Keyset[T]) Next(ctx context.Context) ([]T, error) {
+	if k.done {
+		return nil, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var page []T
+	if err := k.query(ctx).Find(&page).Error; err != nil {
+		return nil, err
+	}
+	if len(page) < k.size {
+		k.done = true
+	}
+	if len(page) > 0 {
+		if err := k.advance(ctx, page[len(page)-1]); err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+// query of the next page.
+func (k *Keyset[T]) query(ctx context.Context) *gorm.DB {
+	if k.size <= 0 {
+		tx := k.db.Session(&gorm.Session{})
+		_ = tx.AddError(errors.New("tdengine: keyset page size must be positive"))
+		return tx
+	}
+	tx := k.db.Session(&gorm.Session{}).WithContext(ctx).Model(new(T))
+	column := clause.Column{Name: k.column}
+	tbname := clause.Column{Name: tbName, Raw: true}
+	op := ">"
+	if k.desc {
+		op = "<"
+	}
+	if c := k.cursor; c != nil {
+		if k.tbName {
+			tx = tx.Where(clause.Expr{
+				SQL:  "(? " + op + " ? OR (? = ? AND ? " + op + " ?))",
+				Vars: []any{column, c.TS, column, c.TS, tbname, c.TbName},
+			})
+		} else {
+			tx = tx.Where(clause.Expr{SQL: "? " + op + " ?", Vars: []any{column, c.TS}})
+		}
+	}
+	orderBy := clause.OrderBy{Columns: []clause.OrderByColumn{{Column: column, Desc: k.desc}}}
+	if k.tbName {
+		orderBy.Columns = append(orderBy.Columns, clause.OrderByColumn{Column: tbname, Desc: k.desc})
+	}
+	return tx.Clauses(orderBy).Limit(k.size)
+}
+
+// advance the cursor to the row.
+func (k *Keyset[T]) advance(ctx context.Context, row T) error {
+	stmt := &gorm.Statement{DB: k.db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return err
+	}
+	s := stmt.Schema
+	rv := reflect.ValueOf(row)
+	var c Cursor
+	field := s.LookUpField(k.column)
+	if field == nil {
+		return fmt.Errorf("tdengine: keyset column %q not found in %s", k.column, s.Name)
+	}
+	switch v, _ := field.ValueOf(ctx, rv); v := v.(type) {
+	case time.Time:
+		c.TS = v
+	case *time.Time:
+		if v != nil {
+			c.TS = *v
+		}
+	default:
+		return fmt.Errorf("tdengine: keyset column %q of %s is not time.Time", k.column, s.Name)
+	}
+	if k.tbName {
+		field = s.LookUpField(tbName)
+		if field == nil {
+			return fmt.Errorf("tdengine: keyset tbname not found in %s", s.Name)
+		}
+		v, _ := field.ValueOf(ctx, rv)
+		name, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("tdengine: keyset tbname of %s is not string", s.Name)
+		}
+		c.TbName = name
+	}
+	k.cursor = &c
+	return nil
+}
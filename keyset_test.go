@@ -0,0 +1,89 @@
+package tdengine_gorm
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func Test_Keyset(t *testing.T) {
+	db, err := gorm.Open(&Dialect{DSN: dsnWithDb}, &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+	last := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name  string
+		pager *Keyset[latestMeter]
+		sql   string
+		vars  int
+	}{
+		{
+			name:  "first page",
+			pager: NewKeyset[latestMeter](db, 100),
+			sql:   "SELECT * FROM `meters` ORDER BY `ts` LIMIT ?",
+			vars:  1,
+		},
+		{
+			name:  "ascending",
+			pager: &Keyset[latestMeter]{db: db, size: 100, column: "ts", cursor: &Cursor{TS: last}},
+			sql:   "SELECT * FROM `meters` WHERE `ts` > ? ORDER BY `ts` LIMIT ?",
+			vars:  2,
+		},
+		{
+			name:  "descending by tbname",
+			pager: (&Keyset[latestMeter]{db: db, size: 100, column: "ts", cursor: &Cursor{TS: last, TbName: "d1001"}}).Desc().ByTbName(),
+			sql:   "SELECT * FROM `meters` WHERE (`ts` < ? OR (`ts` = ? AND tbname < ?)) ORDER BY `ts` DESC,tbname DESC LIMIT ?",
+			vars:  4,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stmt := tc.pager.query(ctx).Find(&[]latestMeter{}).Statement
+			if stmt.Error != nil {
+				t.Fatalf("unexpected error: %v", stmt.Error)
+			}
+			if got := strings.TrimSpace(stmt.SQL.String()); got != tc.sql {
+				t.Errorf("expect sql: %s, got: %s", tc.sql, got)
+			}
+			if len(stmt.Vars) != tc.vars {
+				t.Errorf("expect %d vars, got: %v", tc.vars, stmt.Vars)
+			}
+		})
+	}
+
+	t.Run("advance and resume", func(t *testing.T) {
+		pager := NewKeyset[latestMeter](db, 100).ByTbName()
+		if err := pager.advance(ctx, latestMeter{TbName: "d1002", TS: last}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resumed := NewKeyset[latestMeter](db, 100).ByTbName()
+		if err := resumed.Resume(pager.Cursor().Token()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if c := resumed.Cursor(); !c.TS.Equal(last) || c.TbName != "d1002" {
+			t.Errorf("unexpected cursor: %+v", c)
+		}
+		if err := resumed.Resume("!"); err == nil {
+			t.Errorf("expect invalid token error")
+		}
+	})
+
+	t.Run("next", func(t *testing.T) {
+		pager := NewKeyset[latestMeter](db, 100)
+		rows, err := pager.Next(ctx)
+		if err != nil || len(rows) != 0 || !pager.Done() {
+			t.Errorf("expect empty last page in dry run, got: %v, %v", rows, err)
+		}
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		if _, err = NewKeyset[latestMeter](db, 100).Next(cancelled); err == nil {
+			t.Errorf("expect context canceled error")
+		}
+	})
+}
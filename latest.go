@@ -0,0 +1,114 @@
+package tdengine_gorm
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/thinkgos/tdengine-gorm/clause/fn"
+	"github.com/thinkgos/tdengine-gorm/clause/partition"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Latest the latest row of every child table of the super table of T,
+// [SELECT LAST_ROW(col) AS col, ..., tag, ..., tbname FROM stb WHERE filters PARTITION BY tbname]
+// each column is selected by LAST_ROW separately with the column name kept, so that the rows are scanned into T,
+// tag fields are selected as they are and filled. A field with column tbname receives the child table name.
+//
+// LAST_ROW is served from the cache only if the CACHEMODEL of the database is last_row or both,
+// otherwise the data are scanned and a warning is logged once per database.
+//
+//	rows, err := Latest[Meter](db, Tag("location").Eq("beijing"))
+func Latest[T any](db *gorm.DB, filters ...clause.Expression) ([]T, error) {
+	var dest []T
+	tx := latestQuery[T](db, filters...)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	if err := tx.InstanceSet(latestKey, true).Find(&dest).Error; err != nil {
+		return nil, err
+	}
+	return dest, nil
+}
+
+// latestQuery the LAST_ROW query of Latest.
+func latestQuery[T any](db *gorm.DB, filters ...clause.Expression) *gorm.DB {
+	tx := db.Model(new(T))
+	if err := tx.Statement.Parse(new(T)); err != nil {
+		_ = tx.AddError(err)
+		return tx
+	}
+	var (
+		columns []string
+		vars    []any
+	)
+	for _, field := range tx.Statement.Schema.Fields {
+		if field.DBName == "" || !field.Readable || field.DBName == tbName {
+			continue
+		}
+		columns = append(columns, "?")
+		if isTagField(field) {
+			vars = append(vars, clause.Column{Name: field.DBName})
+		} else {
+			vars = append(vars, fn.LastRow(field.DBName).As(field.DBName))
+		}
+	}
+	columns = append(columns, "?")
+	vars = append(vars, TbName())
+	tx = tx.Select(strings.Join(columns, ","), vars...)
+	for _, filter := range filters {
+		tx = tx.Where(filter)
+	}
+	return tx.Clauses(partition.PartitionBy{}.TbName())
+}
+
+// cacheModelLastRow cache models which keep the last row.
+var cacheModelLastRow = map[string]bool{
+	"last_row": true,
+	"both":     true,
+}
+
+// latestKey instance key which marks the query of Latest.
+const latestKey = "tdengine:latest"
+
+// cacheModels check the cache model of the databases queried by Latest,
+// each database is checked once, a failed check included.
+type cacheModels struct {
+	database string // default database of the DSN
+	checked  sync.Map
+}
+
+func newCacheModels(dialect Dialect) *cacheModels {
+	return &cacheModels{database: databaseOfDSN(dialect.DSN)}
+}
+
+// warn log a warning if the cache model of the database does not keep the last row.
+func (c *cacheModels) warn(db *gorm.DB) {
+	if db.Error != nil || db.DryRun {
+		return
+	}
+	if _, ok := db.InstanceGet(latestKey); !ok {
+		return
+	}
+	database := databaseOf(db.Statement)
+	if database == "" {
+		database = c.database
+	}
+	if database == "" {
+		return
+	}
+	if _, loaded := c.checked.LoadOrStore(database, struct{}{}); loaded {
+		return
+	}
+	var cacheModel string
+	err := db.Statement.ConnPool.QueryRowContext(
+		db.Statement.Context,
+		"SELECT `cachemodel` FROM information_schema.ins_databases WHERE `name` = ?", database,
+	).Scan(&cacheModel)
+	if err != nil || cacheModelLastRow[cacheModel] {
+		return
+	}
+	db.Logger.Warn(db.Statement.Context,
+		fmt.Sprintf("tdengine: CACHEMODEL of database %s is %s, LAST_ROW scans the data, use last_row or both to serve it from the cache", database, cacheModel))
+}
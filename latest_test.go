@@ -0,0 +1,79 @@
+package tdengine_gorm
+
+import (
+	"database/sql/driver"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type latestMeter struct {
+	TbName   string `gorm:"column:tbname;->"`
+	TS       time.Time
+	Current  float64
+	Location string `gorm:"->" tdengine:"tag"`
+}
+
+func (latestMeter) TableName() string {
+	return "meters"
+}
+
+func Test_Latest(t *testing.T) {
+	db, err := gorm.Open(&Dialect{DSN: dsnWithDb}, &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stmt := latestQuery[latestMeter](db, Tag("location").Eq("bj")).Find(&[]latestMeter{}).Statement
+	if stmt.Error != nil {
+		t.Fatalf("unexpected error: %v", stmt.Error)
+	}
+	want := "SELECT LAST_ROW(`ts`) AS `ts`,LAST_ROW(`current`) AS `current`,`location`,tbname FROM `meters` WHERE `location` = ? PARTITION BY tbname"
+	if got := strings.TrimSpace(stmt.SQL.String()); got != want {
+		t.Errorf("expect sql: %s, got: %s", want, got)
+	}
+	if len(stmt.Vars) != 1 {
+		t.Errorf("expect 1 var, got: %v", stmt.Vars)
+	}
+
+	rows, err := Latest[latestMeter](db.Table("power.meters"))
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(rows) != 0 {
+		t.Errorf("expect no rows in dry run, got: %v", rows)
+	}
+}
+
+func Test_LatestCacheModelChecked(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		checks int
+	)
+	fake := &fakeRowsDriver{
+		query: func(query string) ([]string, [][]driver.Value, error) {
+			if strings.Contains(query, "information_schema.ins_databases") {
+				mu.Lock()
+				checks++
+				mu.Unlock()
+				return []string{"cachemodel"}, [][]driver.Value{{"none"}}, nil
+			}
+			return []string{"ts"}, nil, nil
+		},
+	}
+	db := openFakeDB(t, fake, dsnWithDb)
+	for i := 0; i < 3; i++ {
+		if _, err := Latest[latestMeter](db); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := db.Table("meters").Find(&[]latestMeter{}).Error; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if checks != 1 {
+		t.Errorf("expect cache model checked once, checked %d times", checks)
+	}
+}
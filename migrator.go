@@ -4,7 +4,10 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/thinkgos/tdengine-gorm/clause/stream"
+	"github.com/thinkgos/tdengine-gorm/clause/tsma"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 	"gorm.io/gorm/migrator"
@@ -88,3 +91,81 @@ func (m Migrator) DropConstraint(value any, name string) error {
 func (m Migrator) AutoMigrate(values ...any) error {
 	return errors.New("AutoMigrate not support")
 }
+
+// StreamInfo stream of information_schema.ins_streams
+type StreamInfo struct {
+	StreamName  string    `gorm:"column:stream_name"`
+	CreateTime  time.Time `gorm:"column:create_time"`
+	SQL         string    `gorm:"column:sql"`
+	Status      string    `gorm:"column:status"`
+	SourceDB    string    `gorm:"column:source_db"`
+	TargetDB    string    `gorm:"column:target_db"`
+	TargetTable string    `gorm:"column:target_table"`
+}
+
+// CreateStream create the stream [CREATE STREAM ...]
+func (m Migrator) CreateStream(s stream.Stream) error {
+	return m.DB.Exec("?", s).Error
+}
+
+// DropStream drop the stream [DROP STREAM IF EXISTS stream_name]
+func (m Migrator) DropStream(name string) error {
+	return m.DB.Exec("DROP STREAM IF EXISTS ?", clause.Table{Name: name}).Error
+}
+
+// PauseStream pause the stream [PAUSE STREAM IF EXISTS stream_name]
+func (m Migrator) PauseStream(name string) error {
+	return m.DB.Exec("PAUSE STREAM IF EXISTS ?", clause.Table{Name: name}).Error
+}
+
+// ResumeStream resume the stream, the data written while paused are ignored if ignoreUntreated
+// [RESUME STREAM IF EXISTS [IGNORE UNTREATED] stream_name]
+func (m Migrator) ResumeStream(name string, ignoreUntreated bool) error {
+	sql := "RESUME STREAM IF EXISTS ?"
+	if ignoreUntreated {
+		sql = "RESUME STREAM IF EXISTS IGNORE UNTREATED ?"
+	}
+	return m.DB.Exec(sql, clause.Table{Name: name}).Error
+}
+
+// ListStreams streams of the current database, all databases if the DSN has no database.
+func (m Migrator) ListStreams() ([]StreamInfo, error) {
+	var streams []StreamInfo
+	tx := m.DB.Table("information_schema.ins_streams").
+		Select("`stream_name`,`create_time`,`sql`,`status`,`source_db`,`target_db`,`target_table`")
+	if database := databaseOfDSN(m.d.DSN); database != "" {
+		tx = tx.Where("`source_db` = ?", database)
+	}
+	err := tx.Find(&streams).Error
+	return streams, err
+}
+
+// TSMAInfo tsma of information_schema.ins_tsmas
+type TSMAInfo struct {
+	TSMAName   string    `gorm:"column:tsma_name"`
+	DBName     string    `gorm:"column:db_name"`
+	TableName  string    `gorm:"column:table_name"`
+	CreateTime time.Time `gorm:"column:create_time"`
+}
+
+// CreateTSMA create the tsma [CREATE [RECURSIVE] TSMA ...]
+func (m Migrator) CreateTSMA(t tsma.TSMA) error {
+	return m.DB.Exec("?", t).Error
+}
+
+// DropTSMA drop the tsma, name can be qualified with the database [DROP TSMA IF EXISTS [db_name.]tsma_name]
+func (m Migrator) DropTSMA(name string) error {
+	return m.DB.Exec("DROP TSMA IF EXISTS ?", clause.Table{Name: name}).Error
+}
+
+// ListTSMAs tsmas of the current database, all databases if the DSN has no database.
+func (m Migrator) ListTSMAs() ([]TSMAInfo, error) {
+	var tsmas []TSMAInfo
+	tx := m.DB.Table("information_schema.ins_tsmas").
+		Select("`tsma_name`,`db_name`,`table_name`,`create_time`")
+	if database := databaseOfDSN(m.d.DSN); database != "" {
+		tx = tx.Where("`db_name` = ?", database)
+	}
+	err := tx.Find(&tsmas).Error
+	return tsmas, err
+}
@@ -0,0 +1,108 @@
+package tdengine_gorm
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/thinkgos/tdengine-gorm/clause/fn"
+	"github.com/thinkgos/tdengine-gorm/clause/stream"
+	"github.com/thinkgos/tdengine-gorm/clause/tsma"
+	"github.com/thinkgos/tdengine-gorm/clause/window"
+)
+
+func Test_MigratorStream(t *testing.T) {
+	var queries []string
+	db := openFakeDB(t, &fakeRowsDriver{
+		query: func(query string) ([]string, [][]driver.Value, error) {
+			queries = append(queries, query)
+			return []string{"stream_name", "status"}, [][]driver.Value{{"s_avg", "ready"}}, nil
+		},
+	}, "root:taosdata@tcp(localhost:6030)/power")
+	m := db.Migrator().(Migrator)
+	query := db.Table("meters").Select("_wstart,avg(`current`)").Clauses(window.SetInterval(window.Duration{Value: 1, Unit: window.Minute}))
+
+	err := m.CreateStream(stream.NewStream("s_avg", "avg_1m", query).Trigger(stream.AtOnce))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = m.PauseStream("s_avg"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = m.ResumeStream("s_avg", true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = m.DropStream("s_avg"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	streams, err := m.ListStreams()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(streams) != 1 || streams[0].StreamName != "s_avg" || streams[0].Status != "ready" {
+		t.Errorf("unexpected streams: %+v", streams)
+	}
+
+	want := []string{
+		"CREATE STREAM `s_avg` TRIGGER AT_ONCE INTO `avg_1m` AS SELECT _wstart,avg(`current`) FROM `meters` INTERVAL(1m)",
+		"PAUSE STREAM IF EXISTS `s_avg`",
+		"RESUME STREAM IF EXISTS IGNORE UNTREATED `s_avg`",
+		"DROP STREAM IF EXISTS `s_avg`",
+		"SELECT `stream_name`,`create_time`,`sql`,`status`,`source_db`,`target_db`,`target_table` FROM `information_schema`.`ins_streams` WHERE `source_db` = ?",
+	}
+	if len(queries) != len(want) {
+		t.Fatalf("expect queries %q, got: %q", want, queries)
+	}
+	for i := range want {
+		if queries[i] != want[i] {
+			t.Errorf("expect query: %s, got: %s", want[i], queries[i])
+		}
+	}
+}
+
+func Test_MigratorTSMA(t *testing.T) {
+	var queries []string
+	db := openFakeDB(t, &fakeRowsDriver{
+		query: func(query string) ([]string, [][]driver.Value, error) {
+			queries = append(queries, query)
+			return []string{"tsma_name", "db_name", "table_name"}, [][]driver.Value{{"meters_1h", "power", "meters"}}, nil
+		},
+	}, "root:taosdata@tcp(localhost:6030)/power")
+	m := db.Migrator().(Migrator)
+	hour := window.Duration{Value: 1, Unit: window.Hour}
+
+	err := m.CreateTSMA(tsma.NewTSMA("meters_1h", "meters", hour, fn.Avg("current"), fn.Max("voltage")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = m.CreateTSMA(tsma.NewRecursiveTSMA("meters_1d", "meters_1h", window.Duration{Value: 1, Unit: window.Day})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = m.DropTSMA("power.meters_1d"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tsmas, err := m.ListTSMAs()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tsmas) != 1 || tsmas[0].TSMAName != "meters_1h" || tsmas[0].TableName != "meters" {
+		t.Errorf("unexpected tsmas: %+v", tsmas)
+	}
+	if err = m.CreateTSMA(tsma.NewTSMA("meters_1s", "meters", window.Duration{Value: 1, Unit: window.Second}, fn.Avg("current"))); err == nil {
+		t.Errorf("expect interval error")
+	}
+
+	want := []string{
+		"CREATE TSMA `meters_1h` ON `meters` FUNCTION(AVG(`current`),MAX(`voltage`)) INTERVAL(1h)",
+		"CREATE RECURSIVE TSMA `meters_1d` ON `meters_1h` INTERVAL(1d)",
+		"DROP TSMA IF EXISTS `power`.`meters_1d`",
+		"SELECT `tsma_name`,`db_name`,`table_name`,`create_time` FROM `information_schema`.`ins_tsmas` WHERE `db_name` = ?",
+	}
+	if len(queries) != len(want) {
+		t.Fatalf("expect queries %q, got: %q", want, queries)
+	}
+	for i := range want {
+		if queries[i] != want[i] {
+			t.Errorf("expect query: %s, got: %s", want[i], queries[i])
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package tdengine_gorm
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thinkgos/tdengine-gorm/clause/interp"
+	"github.com/thinkgos/tdengine-gorm/clause/window"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Precision timestamp precision of the database.
+type Precision string
+
+const (
+	PrecisionMillisecond Precision = "ms"
+	PrecisionMicrosecond Precision = "us"
+	PrecisionNanosecond  Precision = "ns"
+)
+
+// Allows report whether the duration unit is not finer than the precision.
+func (p Precision) Allows(d window.Duration) bool {
+	switch d.Unit {
+	case window.Nanosecond:
+		return p == PrecisionNanosecond
+	case window.Microsecond:
+		return p == PrecisionMicrosecond || p == PrecisionNanosecond
+	}
+	return true
+}
+
+// detectRetryDelay delay before the failed detection of the database precision is retried.
+var detectRetryDelay = time.Minute
+
+// detection detected precision of the database, or the time of the failed detection.
+type detection struct {
+	precision Precision
+	failedAt  time.Time
+}
+
+// precisions resolve the precision of the databases, configured or detected from information_schema.
+type precisions struct {
+	dialect  Dialect
+	database string // default database of the DSN
+	detected sync.Map
+}
+
+func newPrecisions(dialect Dialect) *precisions {
+	return &precisions{dialect: dialect, database: databaseOfDSN(dialect.DSN)}
+}
+
+// databaseOfDSN database name of the DSN, e.g. root:taosdata@tcp(localhost:6030)/test?loc=Local
+func databaseOfDSN(dsn string) string {
+	if i := strings.Index(dsn, "?"); i >= 0 {
+		dsn = dsn[:i]
+	}
+	if i := strings.LastIndex(dsn, "/"); i >= 0 && !strings.Contains(dsn[i:], ")") {
+		return dsn[i+1:]
+	}
+	return ""
+}
+
+// databaseOf database name of the qualified table, e.g. db.table
+func databaseOf(stmt *gorm.Statement) string {
+	table := stmt.Table
+	if stmt.TableExpr != nil {
+		table = stmt.TableExpr.SQL
+	}
+	if strings.ContainsAny(table, " (") {
+		return ""
+	}
+	if i := strings.Index(table, "."); i > 0 {
+		return strings.Trim(table[:i], "`")
+	}
+	return ""
+}
+
+// lookup precision of the database, empty if unknown.
+func (p *precisions) lookup(db *gorm.DB, database string) Precision {
+	if database == "" {
+		database = p.database
+	}
+	if database != "" {
+		if v, ok := p.dialect.DatabasePrecisions[database]; ok {
+			return v
+		}
+		v, ok := p.detected.Load(database)
+		if ok && v.(detection).failedAt.IsZero() {
+			if v.(detection).precision != "" {
+				return v.(detection).precision
+			}
+		} else if (!ok || time.Since(v.(detection).failedAt) >= detectRetryDelay) && p.dialect.DetectPrecision && !db.DryRun {
+			var precision string
+			err := db.Statement.ConnPool.QueryRowContext(
+				db.Statement.Context,
+				"SELECT `precision` FROM information_schema.ins_databases WHERE `name` = ?", database,
+			).Scan(&precision)
+			// failure falls back to the default precision, and is retried after the delay.
+			if err != nil {
+				p.detected.Store(database, detection{failedAt: time.Now()})
+			} else {
+				p.detected.Store(database, detection{precision: Precision(precision)})
+				if precision != "" {
+					return Precision(precision)
+				}
+			}
+		}
+	}
+	return p.dialect.Precision
+}
+
+// build the WINDOW and EVERY clauses, the durations are checked against the database precision while the clause is built,
+// the clause is written anyway, so that a subquery stays complete and its error is passed on by guardSubqueries.
+func (p *precisions) build(c clause.Clause, builder clause.Builder) {
+	if stmt, ok := builder.(*gorm.Statement); ok {
+		p.check(stmt, c.Expression)
+	}
+	c.Build(builder)
+}
+
+func (p *precisions) check(stmt *gorm.Statement, expr clause.Expression) {
+	var durations []window.Duration
+	switch e := expr.(type) {
+	case window.Window:
+		durations = e.Durations()
+	case interp.Every:
+		durations = []window.Duration{e.Duration}
+	}
+	if len(durations) == 0 {
+		return
+	}
+	precision := p.lookup(stmt.DB, databaseOf(stmt))
+	if precision == "" {
+		return
+	}
+	for _, d := range durations {
+		if !precision.Allows(d) {
+			_ = stmt.AddError(fmt.Errorf("tdengine: duration %s is finer than the database precision %s", d, precision))
+			return
+		}
+	}
+}
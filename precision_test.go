@@ -0,0 +1,189 @@
+package tdengine_gorm
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thinkgos/tdengine-gorm/clause/interp"
+	"github.com/thinkgos/tdengine-gorm/clause/stream"
+	"github.com/thinkgos/tdengine-gorm/clause/window"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func Test_Precision(t *testing.T) {
+	db, err := gorm.Open(&Dialect{
+		DSN:                dsnWithDb,
+		Precision:          PrecisionMillisecond,
+		DatabasePrecisions: map[string]Precision{"nano_db": PrecisionNanosecond},
+	}, &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		name    string
+		table   string
+		clause  clause.Expression
+		wantErr bool
+	}{
+		{
+			name:    "interval micro on ms",
+			table:   "meters",
+			clause:  window.SetInterval(window.Duration{Value: 500, Unit: window.Microsecond}),
+			wantErr: true,
+		},
+		{
+			name:    "sliding nano on ms",
+			table:   "meters",
+			clause:  window.SetInterval(window.Duration{Value: 1, Unit: window.Second}).SetSliding(window.Duration{Value: 1, Unit: window.Nanosecond}),
+			wantErr: true,
+		},
+		{
+			name:    "session micro on ms",
+			table:   "meters",
+			clause:  window.SetSessionWindow("ts", window.Duration{Value: 10, Unit: window.Microsecond}),
+			wantErr: true,
+		},
+		{
+			name:    "every micro on ms",
+			table:   "meters",
+			clause:  interp.Every{Duration: window.Duration{Value: 10, Unit: window.Microsecond}},
+			wantErr: true,
+		},
+		{
+			name:   "interval ms on ms",
+			table:  "meters",
+			clause: window.SetInterval(window.Duration{Value: 500, Unit: window.Millisecond}),
+		},
+		{
+			name:   "interval nano on ns database",
+			table:  "nano_db.meters",
+			clause: window.SetInterval(window.Duration{Value: 500, Unit: window.Nanosecond}),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := db.Table(tc.table).Clauses(tc.clause).Find(&[]map[string]any{}).Error
+			if tc.wantErr != (err != nil) {
+				t.Errorf("expect error: %v, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func Test_DatabaseOfDSN(t *testing.T) {
+	testCases := map[string]string{
+		"root:taosdata@tcp(localhost:6030)/":               "",
+		"root:taosdata@tcp(localhost:6030)/gorm_test":      "gorm_test",
+		"root:taosdata@tcp(localhost:6030)/test?loc=Local": "test",
+		"root:taosdata@/tcp(127.0.0.1:6030)":               "",
+	}
+	for dsn, want := range testCases {
+		if got := databaseOfDSN(dsn); got != want {
+			t.Errorf("%s: expect %q, got %q", dsn, want, got)
+		}
+	}
+}
+
+func Test_PrecisionRowAndDetect(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		detects int
+	)
+	fail := true
+	fake := &fakeRowsDriver{
+		query: func(query string) ([]string, [][]driver.Value, error) {
+			if strings.Contains(query, "information_schema.ins_databases") {
+				mu.Lock()
+				defer mu.Unlock()
+				detects++
+				if fail {
+					return nil, nil, errors.New("no permission")
+				}
+				return []string{"precision"}, [][]driver.Value{{"us"}}, nil
+			}
+			return []string{"v"}, nil, nil
+		},
+	}
+	db := openFakeDialect(t, fake, Dialect{DSN: dsnWithDb, Precision: PrecisionMillisecond, DetectPrecision: true})
+	micro := window.SetInterval(window.Duration{Value: 5, Unit: window.Microsecond})
+
+	rows, err := db.Table("meters").Clauses(micro).Rows()
+	if err == nil {
+		_ = rows.Close()
+		t.Errorf("expect precision error on rows")
+	}
+	if err = db.Table("meters").Clauses(micro).Scan(&[]streamBucket{}).Error; err == nil {
+		t.Errorf("expect precision error on scan")
+	}
+	Stream[streamBucket](context.Background(), db.Table("meters").Clauses(micro))(func(row streamBucket, err error) bool {
+		if err == nil {
+			t.Errorf("expect precision error on stream, got row: %v", row)
+		}
+		return false
+	})
+	if detects != 1 {
+		t.Errorf("expect failed detection cached, detected %d times", detects)
+	}
+
+	// the failed detection is retried after the delay.
+	defer func(delay time.Duration) { detectRetryDelay = delay }(detectRetryDelay)
+	detectRetryDelay = 0
+	fail = false
+	if err = db.Table("meters").Clauses(micro).Scan(&[]streamBucket{}).Error; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err = db.Table("meters").Clauses(micro).Scan(&[]streamBucket{}).Error; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if detects != 2 {
+		t.Errorf("expect detection retried once, detected %d times", detects)
+	}
+}
+
+func Test_PrecisionSubquery(t *testing.T) {
+	var queries []string
+	db := openFakeDialect(t, &fakeRowsDriver{
+		query: func(query string) ([]string, [][]driver.Value, error) {
+			queries = append(queries, query)
+			return []string{"v"}, nil, nil
+		},
+	}, Dialect{DSN: dsnWithDb, Precision: PrecisionMillisecond})
+	m := db.Migrator().(Migrator)
+	hour := window.Duration{Value: 1, Unit: window.Hour}
+	inner := func() *gorm.DB {
+		return db.Table("meters").Select("_wstart AS `ts`,avg(`current`) AS `v`").
+			Clauses(window.SetInterval(window.Duration{Value: 500, Unit: window.Microsecond}))
+	}
+
+	if err := Downsample(db, inner(), hour).Select("_wstart,avg(`v`)").Find(&[]map[string]any{}).Error; err == nil {
+		t.Errorf("downsample: expect precision error")
+	}
+	if err := UnionAll(db, inner(), inner()).Find(&[]map[string]any{}).Error; err == nil {
+		t.Errorf("union all: expect precision error")
+	}
+	if err := db.Table("meters").Where("`ts` IN (?)", inner().Select("_wstart")).Find(&[]map[string]any{}).Error; err == nil {
+		t.Errorf("where subquery: expect precision error")
+	}
+	if err := m.CreateStream(stream.NewStream("s", "t", inner())); err == nil {
+		t.Errorf("stream: expect precision error")
+	}
+	if err := m.CreateTopic("tp", inner()); err == nil {
+		t.Errorf("topic: expect precision error")
+	}
+	if len(queries) != 0 {
+		t.Errorf("expect no statement sent, got: %v", queries)
+	}
+
+	// the clause is written even if rejected.
+	stmt := inner().Session(&gorm.Session{DryRun: true}).Find(&[]map[string]any{}).Statement
+	if stmt.Error == nil || !strings.Contains(stmt.SQL.String(), "INTERVAL(500u)") {
+		t.Errorf("expect precision error with complete sql, got: %v, %s", stmt.Error, stmt.SQL.String())
+	}
+}
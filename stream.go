@@ -0,0 +1,49 @@
+package tdengine_gorm
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Stream scan the rows of the query into T one by one instead of loading all of them,
+// the *sql.Rows are released when the iteration ends, breaks early or the context is done.
+// NULL columns, e.g. output of FILL(NULL), are scanned as nil into pointer or sql.Null* fields and zero value otherwise.
+//
+// The iterator has the signature of iter.Seq2[T, error], it can be ranged over since Go 1.23:
+//
+//	for row, err := range Stream[Bucket](ctx, db.Table("meters").Select("_wstart,avg(`current`) AS `v`").Clauses(window.SetInterval(d))) {
+//		if err != nil {
+//			return err
+//		}
+//		// use row
+//	}
+func Stream[T any](ctx context.Context, db *gorm.DB) func(yield func(T, error) bool) {
+	return func(yield func(T, error) bool) {
+		var zero T
+		tx := db.WithContext(ctx).Model(new(T))
+		rows, err := tx.Rows()
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		defer rows.Close()
+		for rows.Next() {
+			if err = ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+			var row T
+			if err = tx.ScanRows(rows, &row); err != nil {
+				yield(zero, err)
+				return
+			}
+			if !yield(row, nil) {
+				return
+			}
+		}
+		if err = rows.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
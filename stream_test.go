@@ -0,0 +1,64 @@
+package tdengine_gorm
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+)
+
+type streamBucket struct {
+	Start time.Time `gorm:"column:_wstart;->"`
+	V     *float64
+}
+
+func Test_Stream(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fake := &fakeRowsDriver{
+		columns: []string{"_wstart", "v"},
+		values: [][]driver.Value{
+			{t0, 1.5},
+			{t0.Add(time.Minute), nil},
+			{t0.Add(2 * time.Minute), 2.5},
+		},
+	}
+	db := openFakeDB(t, fake, "")
+	ctx := context.Background()
+	query := db.Table("meters").Select("_wstart,avg(`current`) AS `v`")
+
+	var rows []streamBucket
+	Stream[streamBucket](ctx, query)(func(row streamBucket, err error) bool {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		rows = append(rows, row)
+		return true
+	})
+	if len(rows) != 3 {
+		t.Fatalf("expect 3 rows, got: %v", rows)
+	}
+	if !rows[1].Start.Equal(t0.Add(time.Minute)) || rows[1].V != nil || rows[2].V == nil || *rows[2].V != 2.5 {
+		t.Errorf("unexpected rows: %+v", rows)
+	}
+
+	closed := fake.closed.Load()
+	n := 0
+	Stream[streamBucket](ctx, query)(func(streamBucket, error) bool {
+		n++
+		return false
+	})
+	if n != 1 || fake.closed.Load() != closed+1 {
+		t.Errorf("expect rows released after early break, yielded %d", n)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	var gotErr error
+	Stream[streamBucket](cancelled, query)(func(_ streamBucket, err error) bool {
+		gotErr = err
+		return err == nil
+	})
+	if gotErr == nil {
+		t.Errorf("expect context canceled error")
+	}
+}
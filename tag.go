@@ -0,0 +1,249 @@
+package tdengine_gorm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// TagKey struct tag key of TDengine settings, mark the field as a tag column of the super table:
+//
+//	type Meter struct {
+//		TS       time.Time
+//		Current  float64
+//		Location string `gorm:"->" tdengine:"tag"`
+//	}
+const TagKey = "tdengine"
+
+// tbName the table name pseudo column of the child table.
+const tbName = "tbname"
+
+func tagSettings(field *schema.Field) map[string]string {
+	return schema.ParseTagSetting(field.Tag.Get(TagKey), ";")
+}
+
+// isTagField report whether the field is a tag column.
+func isTagField(field *schema.Field) bool {
+	_, ok := tagSettings(field)["TAG"]
+	return ok
+}
+
+// tagFields tag fields of the schema in declaration order.
+func tagFields(s *schema.Schema) []*schema.Field {
+	fields := make([]*schema.Field, 0, 4)
+	for _, field := range s.Fields {
+		if field.DBName != "" && isTagField(field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// lookUpTag look up the tag field by db name or field name.
+func lookUpTag(s *schema.Schema, name string) *schema.Field {
+	for _, field := range tagFields(s) {
+		if field.DBName == name || field.Name == name {
+			return field
+		}
+	}
+	return nil
+}
+
+// Operand left operand of the conditions, e.g. tag column, tbname or json key of json tag.
+type Operand struct {
+	expr clause.Expression
+}
+
+// Build write the operand.
+func (o Operand) Build(builder clause.Builder) {
+	o.expr.Build(builder)
+}
+
+func (o Operand) cond(sql string, vars ...any) clause.Expression {
+	return clause.Expr{SQL: "? " + sql, Vars: append([]any{o.expr}, vars...)}
+}
+
+// Eq operand = value
+func (o Operand) Eq(value any) clause.Expression {
+	return o.cond("= ?", value)
+}
+
+// Neq operand <> value
+func (o Operand) Neq(value any) clause.Expression {
+	return o.cond("<> ?", value)
+}
+
+// Gt operand > value
+func (o Operand) Gt(value any) clause.Expression {
+	return o.cond("> ?", value)
+}
+
+// Gte operand >= value
+func (o Operand) Gte(value any) clause.Expression {
+	return o.cond(">= ?", value)
+}
+
+// Lt operand < value
+func (o Operand) Lt(value any) clause.Expression {
+	return o.cond("< ?", value)
+}
+
+// Lte operand <= value
+func (o Operand) Lte(value any) clause.Expression {
+	return o.cond("<= ?", value)
+}
+
+// In operand IN (values...), a single slice is expanded into the values, e.g. In(names) with names []string.
+func (o Operand) In(values ...any) clause.Expression {
+	values = expand(values)
+	if len(values) == 0 {
+		return clause.Expr{SQL: "1 = 0"}
+	}
+	return o.cond("IN (?"+strings.Repeat(",?", len(values)-1)+")", values...)
+}
+
+// NotIn operand NOT IN (values...), a single slice is expanded into the values like In.
+func (o Operand) NotIn(values ...any) clause.Expression {
+	values = expand(values)
+	if len(values) == 0 {
+		return clause.Expr{SQL: "1 = 1"}
+	}
+	return o.cond("NOT IN (?"+strings.Repeat(",?", len(values)-1)+")", values...)
+}
+
+// expand the values of the single slice or array argument.
+func expand(values []any) []any {
+	if len(values) != 1 {
+		return values
+	}
+	rv := reflect.ValueOf(values[0])
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return values
+	}
+	expanded := make([]any, rv.Len())
+	for i := range expanded {
+		expanded[i] = rv.Index(i).Interface()
+	}
+	return expanded
+}
+
+// Like operand LIKE pattern
+func (o Operand) Like(pattern string) clause.Expression {
+	return o.cond("LIKE ?", pattern)
+}
+
+// Match operand MATCH regex, the operand matches the POSIX regular expression.
+func (o Operand) Match(regex string) clause.Expression {
+	return o.cond("MATCH ?", regex)
+}
+
+// NMatch operand NMATCH regex, the operand does not match the POSIX regular expression.
+func (o Operand) NMatch(regex string) clause.Expression {
+	return o.cond("NMATCH ?", regex)
+}
+
+// IsNull operand IS NULL
+func (o Operand) IsNull() clause.Expression {
+	return o.cond("IS NULL")
+}
+
+// IsNotNull operand IS NOT NULL
+func (o Operand) IsNotNull() clause.Expression {
+	return o.cond("IS NOT NULL")
+}
+
+// TagColumn tag column or tbname of the super table used in conditions.
+// The tag name is checked against the tag fields of the statement model when the statement is built,
+// a misspelled tag is only reported for models with tag fields, the name is written as is
+// without a model, e.g. db.Table("meters"), or with a model without tag fields.
+type TagColumn struct {
+	Operand
+}
+
+// Tag tag column of the super table.
+func Tag(name string) TagColumn {
+	return TagColumn{Operand{tagRef{name: name}}}
+}
+
+// TbName the tbname pseudo column.
+func TbName() TagColumn {
+	return TagColumn{Operand{tagRef{name: tbName}}}
+}
+
+// Key json key access of the json tag [tag->'key']
+func (t TagColumn) Key(key string) Operand {
+	return Operand{clause.Expr{SQL: "?->?", Vars: []any{t.expr, key}}}
+}
+
+// Contains json tag contains the key [tag CONTAINS 'key']
+func (t TagColumn) Contains(key string) clause.Expression {
+	return t.cond("CONTAINS ?", key)
+}
+
+// tagRef reference of the tag column.
+type tagRef struct {
+	name string
+}
+
+// Build write the tag column, check the name against the model tags if any, otherwise the name is not checked.
+func (t tagRef) Build(builder clause.Builder) {
+	if t.name == tbName {
+		_, _ = builder.WriteString(tbName)
+		return
+	}
+	if stmt, ok := builder.(*gorm.Statement); ok && stmt.Schema != nil && len(tagFields(stmt.Schema)) > 0 {
+		field := lookUpTag(stmt.Schema, t.name)
+		if field == nil {
+			_ = builder.AddError(fmt.Errorf("tdengine: tag %q not found in %s", t.name, stmt.Schema.Name))
+			return
+		}
+		builder.WriteQuoted(field.DBName)
+		return
+	}
+	builder.WriteQuoted(t.name)
+}
+
+// DistinctTags select distinct tag values of the super table [SELECT DISTINCT tag_list]
+//
+//	db.Model(&Meter{}).Clauses(DistinctTags("location")).Find(&locations)
+func DistinctTags(tags ...string) clause.Select {
+	vars := make([]any, 0, len(tags))
+	for _, tag := range tags {
+		vars = append(vars, Tag(tag))
+	}
+	return clause.Select{
+		Distinct:   true,
+		Expression: clause.Expr{SQL: strings.TrimSuffix(strings.Repeat("?,", len(vars)), ","), Vars: vars},
+	}
+}
+
+// ShowTableTags list tbname and tags of the child tables [SHOW TABLE TAGS [tag_list] FROM table]
+// table can be a super table or a child table, tags are checked against the tag fields of dest.
+func ShowTableTags(db *gorm.DB, table string, dest any, tags ...string) error {
+	stmt := &gorm.Statement{DB: db}
+	checked := stmt.Parse(dest) == nil && len(tagFields(stmt.Schema)) > 0
+	sql := "SHOW TABLE TAGS "
+	vars := make([]any, 0, len(tags)+1)
+	for i, tag := range tags {
+		if checked {
+			field := lookUpTag(stmt.Schema, tag)
+			if field == nil {
+				return fmt.Errorf("tdengine: tag %q not found in %s", tag, stmt.Schema.Name)
+			}
+			tag = field.DBName
+		}
+		if i > 0 {
+			sql += ","
+		}
+		sql += "?"
+		vars = append(vars, clause.Column{Name: tag})
+	}
+	if len(tags) > 0 {
+		sql += " "
+	}
+	return db.Raw(sql+"FROM ?", append(vars, clause.Table{Name: table})...).Scan(dest).Error
+}
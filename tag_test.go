@@ -0,0 +1,128 @@
+package tdengine_gorm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type tagMeter struct {
+	TS       time.Time
+	Current  float64
+	Location string               `gorm:"->" tdengine:"tag"`
+	GroupID  int                  `gorm:"->" tdengine:"tag"`
+	Info     JSON[map[string]any] `gorm:"->" tdengine:"tag"`
+}
+
+func (tagMeter) TableName() string {
+	return "meters"
+}
+
+func Test_Tag(t *testing.T) {
+	db, err := gorm.Open(&Dialect{DSN: dsnWithDb}, &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		name    string
+		run     func(tx *gorm.DB) *gorm.DB
+		sql     string
+		vars    int
+		wantErr bool
+	}{
+		{
+			name: "tag and tbname",
+			run: func(tx *gorm.DB) *gorm.DB {
+				return tx.Model(&tagMeter{}).
+					Where(Tag("location").Eq("bj")).
+					Where(Tag("GroupID").In(1, 2)).
+					Where(TbName().In("d1001", "d1002")).
+					Find(&[]tagMeter{})
+			},
+			sql:  "SELECT * FROM `meters` WHERE `location` = ? AND `group_id` IN (?,?) AND tbname IN (?,?)",
+			vars: 5,
+		},
+		{
+			name: "in slice",
+			run: func(tx *gorm.DB) *gorm.DB {
+				names := []string{"d1001", "d1002"}
+				return tx.Model(&tagMeter{}).
+					Where(TbName().In(names)).
+					Where(Tag("GroupID").NotIn([]int{})).
+					Where(Tag("info").Key("model").In([2]string{"m1", "m2"})).
+					Find(&[]tagMeter{})
+			},
+			sql:  "SELECT * FROM `meters` WHERE tbname IN (?,?) AND 1 = 1 AND `info`->? IN (?,?)",
+			vars: 5,
+		},
+		{
+			name: "unknown tag",
+			run: func(tx *gorm.DB) *gorm.DB {
+				return tx.Model(&tagMeter{}).Where(Tag("city").Eq("bj")).Find(&[]tagMeter{})
+			},
+			wantErr: true,
+		},
+		{
+			name: "without model",
+			run: func(tx *gorm.DB) *gorm.DB {
+				return tx.Table("meters").Where(Tag("city").Like("b%")).Find(&[]map[string]any{})
+			},
+			sql:  "SELECT * FROM `meters` WHERE `city` LIKE ?",
+			vars: 1,
+		},
+		{
+			name: "json tag",
+			run: func(tx *gorm.DB) *gorm.DB {
+				return tx.Model(&tagMeter{}).
+					Where(Tag("info").Contains("model")).
+					Where(Tag("info").Key("model").Eq("m1")).
+					Where(Tag("location").Match("^b")).
+					Where(TbName().NMatch("^x")).
+					Find(&[]tagMeter{})
+			},
+			sql:  "SELECT * FROM `meters` WHERE `info` CONTAINS ? AND `info`->? = ? AND `location` MATCH ? AND tbname NMATCH ?",
+			vars: 5,
+		},
+		{
+			name: "distinct tags",
+			run: func(tx *gorm.DB) *gorm.DB {
+				return tx.Model(&tagMeter{}).Clauses(DistinctTags("location", "group_id")).Find(&[]tagMeter{})
+			},
+			sql: "SELECT DISTINCT `location`,`group_id` FROM `meters`",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stmt := tc.run(db.Session(&gorm.Session{})).Statement
+			if tc.wantErr {
+				if stmt.Error == nil {
+					t.Errorf("expect error")
+				}
+				return
+			}
+			if stmt.Error != nil {
+				t.Fatalf("unexpected error: %v", stmt.Error)
+			}
+			if got := strings.TrimSpace(stmt.SQL.String()); got != tc.sql {
+				t.Errorf("expect sql: %s, got: %s", tc.sql, got)
+			}
+			if len(stmt.Vars) != tc.vars {
+				t.Errorf("expect %d vars, got: %v", tc.vars, stmt.Vars)
+			}
+		})
+	}
+
+	t.Run("show table tags", func(t *testing.T) {
+		tx := db.Session(&gorm.Session{})
+		if err := ShowTableTags(tx, "meters", &[]tagMeter{}, "city"); err == nil {
+			t.Errorf("expect error")
+		}
+		if err := ShowTableTags(tx, "meters", &[]tagMeter{}, "location"); err != nil && !errors.Is(err, gorm.ErrDryRunModeUnsupported) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
@@ -2,7 +2,6 @@ package tdengine_gorm
 
 import (
 	"database/sql"
-	"errors"
 	"fmt"
 
 	_ "github.com/taosdata/driver-go/v3/taosSql"
@@ -24,6 +23,16 @@ type Dialect struct {
 	DriverName string
 	DSN        string
 	Conn       gorm.ConnPool
+	// NoopTransaction run gorm transactions as no-op instead of returning ErrUnsupported,
+	// so that code written for other dialects still works. Nothing is rolled back.
+	NoopTransaction bool
+	// Precision default timestamp precision of the databases,
+	// durations of WINDOW and EVERY clauses finer than it are rejected before the statement is sent.
+	Precision Precision
+	// DatabasePrecisions timestamp precision of each database, overrides Precision.
+	DatabasePrecisions map[string]Precision
+	// DetectPrecision query the precision of the database from information_schema if not configured.
+	DetectPrecision bool
 }
 
 func (Dialect) Name() string {
@@ -46,15 +55,25 @@ func (dialect Dialect) Initialize(db *gorm.DB) (err error) {
 			return err
 		}
 	}
+	db.ConnPool = &connPool{ConnPool: db.ConnPool, noopTransaction: dialect.NoopTransaction}
 	callbacks.RegisterDefaultCallbacks(db, &callbacks.Config{
 		LastInsertIDReversed: true,
-		QueryClauses:         []string{"SELECT", "FROM", "WHERE", "WINDOW", "FILL", "GROUP BY", "ORDER BY", "SLIMIT", "LIMIT"},
-		CreateClauses:        []string{"CREATE TABLE", "INSERT", "USING", "VALUES", "ON CONFLICT", "RETURNING"},
+		QueryClauses:         utils.QueryClauses,
+		CreateClauses:        utils.CreateClauses,
 	})
+	if err = registerGuards(db); err != nil {
+		return err
+	}
+	if err = db.Callback().Query().Before("gorm:query").Register("tdengine:cachemodel", newCacheModels(dialect).warn); err != nil {
+		return err
+	}
 
 	for k, v := range dialect.ClauseBuilders() {
 		db.ClauseBuilders[k] = v
 	}
+	precisions := newPrecisions(dialect)
+	db.ClauseBuilders["WINDOW"] = precisions.build
+	db.ClauseBuilders["EVERY"] = precisions.build
 	return nil
 }
 
@@ -164,10 +183,16 @@ func (Dialect) DataTypeOf(field *schema.Field) string {
 	}
 }
 
-func (Dialect) SavePoint(tx *gorm.DB, name string) error {
-	return errors.New("not support transaction")
+func (dialect Dialect) SavePoint(tx *gorm.DB, name string) error {
+	if dialect.NoopTransaction {
+		return nil
+	}
+	return ErrUnsupported{Feature: "savepoint"}
 }
 
-func (Dialect) RollbackTo(tx *gorm.DB, name string) error {
-	return errors.New("not support transaction")
+func (dialect Dialect) RollbackTo(tx *gorm.DB, name string) error {
+	if dialect.NoopTransaction {
+		return nil
+	}
+	return ErrUnsupported{Feature: "savepoint"}
 }
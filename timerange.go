@@ -0,0 +1,38 @@
+package tdengine_gorm
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TimeRange scope of the half-open time range [col >= from AND col < to].
+// from and to can be time.Time bound as parameter, or a server side expression like fn.Now().Sub(...),
+// nil or zero time.Time means no bound.
+//
+//	db.Scopes(TimeRange("ts", fn.Now().Sub(window.Duration{Value: 15, Unit: window.Minute}), nil))
+func TimeRange(col string, from, to any) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		column := clause.Column{Name: col}
+		if !isOpenBound(from) {
+			db = db.Where(clause.Expr{SQL: "? >= ?", Vars: []any{column, from}})
+		}
+		if !isOpenBound(to) {
+			db = db.Where(clause.Expr{SQL: "? < ?", Vars: []any{column, to}})
+		}
+		return db
+	}
+}
+
+func isOpenBound(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case time.Time:
+		return v.IsZero()
+	case *time.Time:
+		return v == nil || v.IsZero()
+	}
+	return false
+}
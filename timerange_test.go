@@ -0,0 +1,67 @@
+package tdengine_gorm
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thinkgos/tdengine-gorm/clause/fn"
+	"github.com/thinkgos/tdengine-gorm/clause/window"
+	"gorm.io/gorm"
+)
+
+func Test_TimeRange(t *testing.T) {
+	db, err := gorm.Open(&Dialect{DSN: dsnWithDb}, &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Now()
+
+	testCases := []struct {
+		name string
+		from any
+		to   any
+		sql  string
+		vars []any
+	}{
+		{
+			name: "go time",
+			from: now.Add(-time.Hour),
+			to:   now,
+			sql:  "SELECT * FROM `meters` WHERE `ts` >= ? AND `ts` < ?",
+			vars: []any{now.Add(-time.Hour), now},
+		},
+		{
+			name: "server time",
+			from: fn.Now().Sub(window.Duration{Value: 15, Unit: window.Minute}),
+			to:   nil,
+			sql:  "SELECT * FROM `meters` WHERE `ts` >= NOW - 15m",
+		},
+		{
+			name: "today",
+			from: fn.Today(),
+			to:   fn.Today().Add(window.Duration{Value: 1, Unit: window.Day}),
+			sql:  "SELECT * FROM `meters` WHERE `ts` >= TODAY() AND `ts` < TODAY() + 1d",
+		},
+		{
+			name: "open",
+			from: time.Time{},
+			to:   nil,
+			sql:  "SELECT * FROM `meters`",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stmt := db.Table("meters").Scopes(TimeRange("ts", tc.from, tc.to)).Find(&[]map[string]any{}).Statement
+			if stmt.Error != nil {
+				t.Fatalf("unexpected error: %v", stmt.Error)
+			}
+			if got := strings.TrimSpace(stmt.SQL.String()); got != tc.sql {
+				t.Errorf("expect sql: %s, got: %s", tc.sql, got)
+			}
+			if len(stmt.Vars) != len(tc.vars) {
+				t.Errorf("expect vars: %v, got: %v", tc.vars, stmt.Vars)
+			}
+		})
+	}
+}
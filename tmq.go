@@ -0,0 +1,178 @@
+package tdengine_gorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/taosdata/driver-go/v3/common/tmq"
+	"github.com/thinkgos/tdengine-gorm/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// CreateTopic create the topic of the query [CREATE TOPIC IF NOT EXISTS topic_name AS subquery]
+// select the columns in the order of the consumer, see NewConsumer.
+func (m Migrator) CreateTopic(name string, query *gorm.DB) error {
+	// gorm drops the error of the query built as a subquery.
+	if err := utils.SubqueryError(query); err != nil {
+		return err
+	}
+	return m.DB.Exec("CREATE TOPIC IF NOT EXISTS ? AS ?", clause.Table{Name: name}, query).Error
+}
+
+// CreateStableTopic create the topic of the super table [CREATE TOPIC IF NOT EXISTS topic_name AS STABLE stb_name]
+func (m Migrator) CreateStableTopic(name, stable string) error {
+	return m.DB.Exec("CREATE TOPIC IF NOT EXISTS ? AS STABLE ?", clause.Table{Name: name}, clause.Table{Name: stable}).Error
+}
+
+// CreateDatabaseTopic create the topic of the database [CREATE TOPIC IF NOT EXISTS topic_name AS DATABASE db_name]
+func (m Migrator) CreateDatabaseTopic(name, database string) error {
+	return m.DB.Exec("CREATE TOPIC IF NOT EXISTS ? AS DATABASE ?", clause.Table{Name: name}, clause.Table{Name: database}).Error
+}
+
+// DropTopic drop the topic [DROP TOPIC IF EXISTS topic_name]
+func (m Migrator) DropTopic(name string) error {
+	return m.DB.Exec("DROP TOPIC IF EXISTS ?", clause.Table{Name: name}).Error
+}
+
+// TMQConsumer the subscribed consumer of driver-go, *af/tmq.Consumer or *ws/tmq.Consumer.
+type TMQConsumer interface {
+	Poll(timeoutMs int) tmq.Event
+	Commit() ([]tmq.TopicPartition, error)
+	Assignment() ([]tmq.TopicPartition, error)
+	Seek(partition tmq.TopicPartition, ignoredTimeoutMs int) error
+	Close() error
+}
+
+// Message data message of the topic decoded into T.
+type Message[T any] struct {
+	Topic     string
+	DBName    string
+	Partition tmq.TopicPartition
+	Rows      []T
+}
+
+// Consumer decode the data messages of the consumer into T.
+// The values of the message rows have no column names, they are mapped to the fields of T by position,
+// in the order of the columns given to NewConsumer, or the columns of T followed by the tags of T,
+// a field with column tbname receives the child table name.
+//
+//	c, _ := tmq.NewConsumer(&tmqcommon.ConfigMap{"group.id": "g1", ...})
+//	_ = c.Subscribe("meters_topic", nil)
+//	consumer, err := NewConsumer[Meter](db, c)
+//	msg, err := consumer.Poll(500)
+type Consumer[T any] struct {
+	consumer TMQConsumer
+	schema   *schema.Schema
+	fields   []*schema.Field
+	tbName   *schema.Field
+}
+
+// NewConsumer wrap the subscribed consumer, columns is the order of the values in the message.
+func NewConsumer[T any](db *gorm.DB, consumer TMQConsumer, columns ...string) (*Consumer[T], error) {
+	s, err := parseRow[T](db)
+	if err != nil {
+		return nil, err
+	}
+	c := &Consumer[T]{consumer: consumer, schema: s, tbName: s.LookUpField(tbName)}
+	if len(columns) > 0 {
+		for _, column := range columns {
+			field := s.LookUpField(column)
+			if field == nil {
+				return nil, fmt.Errorf("tdengine: column %q not found in %s", column, s.Name)
+			}
+			c.fields = append(c.fields, field)
+		}
+		return c, nil
+	}
+	for _, field := range s.Fields {
+		if field.DBName != "" && field.DBName != tbName && !isTagField(field) {
+			c.fields = append(c.fields, field)
+		}
+	}
+	c.fields = append(c.fields, tagFields(s)...)
+	return c, nil
+}
+
+// Poll poll a message, nil if timeout, meta messages are returned without rows.
+func (c *Consumer[T]) Poll(timeoutMs int) (*Message[T], error) {
+	switch e := c.consumer.Poll(timeoutMs).(type) {
+	case nil:
+		return nil, nil
+	case tmq.Error:
+		return nil, e
+	case *tmq.DataMessage:
+		rows, err := c.decode(e.Value())
+		if err != nil {
+			return nil, err
+		}
+		return &Message[T]{Topic: e.Topic(), DBName: e.DBName(), Partition: e.TopicPartition, Rows: rows}, nil
+	case *tmq.MetaDataMessage:
+		var rows []T
+		if v, ok := e.Value().(*tmq.MetaData); ok && v != nil {
+			var err error
+			if rows, err = c.decode(v.Data); err != nil {
+				return nil, err
+			}
+		}
+		return &Message[T]{Topic: e.Topic(), DBName: e.DBName(), Partition: e.TopicPartition, Rows: rows}, nil
+	case *tmq.MetaMessage:
+		return &Message[T]{Topic: e.Topic(), DBName: e.DBName(), Partition: e.TopicPartition}, nil
+	default:
+		return nil, fmt.Errorf("tdengine: unexpected tmq event %v", e)
+	}
+}
+
+// Commit commit the offsets of the consumed messages.
+func (c *Consumer[T]) Commit() ([]tmq.TopicPartition, error) {
+	return c.consumer.Commit()
+}
+
+// Assignment the partitions assigned to the consumer.
+func (c *Consumer[T]) Assignment() ([]tmq.TopicPartition, error) {
+	return c.consumer.Assignment()
+}
+
+// Seek set the offset of the partition.
+func (c *Consumer[T]) Seek(partition tmq.TopicPartition) error {
+	return c.consumer.Seek(partition, 0)
+}
+
+// Close close the consumer.
+func (c *Consumer[T]) Close() error {
+	return c.consumer.Close()
+}
+
+// decode the data blocks into T.
+func (c *Consumer[T]) decode(value any) ([]T, error) {
+	blocks, _ := value.([]*tmq.Data)
+	ctx := context.Background()
+	var rows []T
+	for _, block := range blocks {
+		if block == nil {
+			continue
+		}
+		for _, values := range block.Data {
+			if len(values) != len(c.fields) {
+				return nil, fmt.Errorf("tdengine: tmq row of %s has %d values, %s has %d columns",
+					block.TableName, len(values), c.schema.Name, len(c.fields))
+			}
+			var row T
+			rv := reflect.ValueOf(&row).Elem()
+			for i, field := range c.fields {
+				if err := field.Set(ctx, rv, values[i]); err != nil {
+					return nil, fmt.Errorf("tdengine: tmq column %s of %s: %w", field.DBName, block.TableName, err)
+				}
+			}
+			if c.tbName != nil {
+				if err := c.tbName.Set(ctx, rv, block.TableName); err != nil {
+					return nil, err
+				}
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
@@ -0,0 +1,160 @@
+package tdengine_gorm
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/taosdata/driver-go/v3/common/tmq"
+	"gorm.io/gorm"
+)
+
+// fakeConsumer in-process consumer which returns the events in order.
+type fakeConsumer struct {
+	events []tmq.Event
+	seeks  []tmq.TopicPartition
+	closed bool
+}
+
+func (c *fakeConsumer) Poll(int) tmq.Event {
+	if len(c.events) == 0 {
+		return nil
+	}
+	e := c.events[0]
+	c.events = c.events[1:]
+	return e
+}
+
+func (c *fakeConsumer) Commit() ([]tmq.TopicPartition, error) { return c.seeks, nil }
+func (c *fakeConsumer) Assignment() ([]tmq.TopicPartition, error) {
+	topic := "meters_topic"
+	return []tmq.TopicPartition{{Topic: &topic, Partition: 2, Offset: 10}}, nil
+}
+func (c *fakeConsumer) Seek(partition tmq.TopicPartition, _ int) error {
+	c.seeks = append(c.seeks, partition)
+	return nil
+}
+func (c *fakeConsumer) Close() error {
+	c.closed = true
+	return nil
+}
+
+func Test_Consumer(t *testing.T) {
+	db, err := gorm.Open(&Dialect{DSN: dsnWithDb}, &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	data := &tmq.DataMessage{}
+	data.SetTopic("meters_topic")
+	data.SetDbName("power")
+	data.SetData([]*tmq.Data{
+		{TableName: "d1001", Data: [][]driver.Value{{t0, 10.5, "beijing"}, {t0.Add(time.Second), nil, "beijing"}}},
+		{TableName: "d1002", Data: [][]driver.Value{{t0, 11.5, "shanghai"}}},
+	})
+	bad := &tmq.DataMessage{}
+	bad.SetData([]*tmq.Data{{TableName: "d1003", Data: [][]driver.Value{{t0}}}})
+	fake := &fakeConsumer{events: []tmq.Event{data, &tmq.MetaMessage{}, tmq.NewTMQError(1, "broken"), bad}}
+
+	consumer, err := NewConsumer[groupMeter](db, fake, "ts", "current", "location")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	msg, err := consumer.Poll(100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if msg.Topic != "meters_topic" || msg.DBName != "power" || len(msg.Rows) != 3 {
+		t.Fatalf("unexpected message: %+v", msg)
+	}
+	if r := msg.Rows[0]; r.TbName != "d1001" || !r.TS.Equal(t0) || r.Current == nil || *r.Current != 10.5 || r.Location != "beijing" {
+		t.Errorf("unexpected row: %+v", r)
+	}
+	if r := msg.Rows[1]; r.Current != nil {
+		t.Errorf("expect null current, got: %v", *r.Current)
+	}
+	if r := msg.Rows[2]; r.TbName != "d1002" || r.Location != "shanghai" {
+		t.Errorf("unexpected row: %+v", r)
+	}
+	if msg, err = consumer.Poll(100); err != nil || len(msg.Rows) != 0 {
+		t.Errorf("expect meta message without rows, got: %+v, %v", msg, err)
+	}
+	var tmqErr tmq.Error
+	if _, err = consumer.Poll(100); !errors.As(err, &tmqErr) {
+		t.Errorf("expect tmq error, got: %v", err)
+	}
+	if _, err = consumer.Poll(100); err == nil {
+		t.Errorf("expect values count error")
+	}
+	if msg, err = consumer.Poll(100); msg != nil || err != nil {
+		t.Errorf("expect nil on timeout, got: %+v, %v", msg, err)
+	}
+
+	partitions, err := consumer.Assignment()
+	if err != nil || len(partitions) != 1 {
+		t.Fatalf("unexpected assignment: %v, %v", partitions, err)
+	}
+	partitions[0].Offset = 0
+	if err = consumer.Seek(partitions[0]); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if committed, err := consumer.Commit(); err != nil || len(committed) != 1 || committed[0].Offset != 0 {
+		t.Errorf("unexpected commit: %v, %v", committed, err)
+	}
+	if err = consumer.Close(); err != nil || !fake.closed {
+		t.Errorf("expect consumer closed")
+	}
+
+	defaults, err := NewConsumer[groupMeter](db, fake)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var columns []string
+	for _, field := range defaults.fields {
+		columns = append(columns, field.DBName)
+	}
+	if want := "ts,current,location,group_id"; strings.Join(columns, ",") != want {
+		t.Errorf("expect default columns %s, got: %v", want, columns)
+	}
+	if _, err = NewConsumer[groupMeter](db, fake, "unknown"); err == nil {
+		t.Errorf("expect column not found error")
+	}
+}
+
+func Test_MigratorTopic(t *testing.T) {
+	var queries []string
+	db := openFakeDB(t, &fakeRowsDriver{
+		query: func(query string) ([]string, [][]driver.Value, error) {
+			queries = append(queries, query)
+			return nil, nil, nil
+		},
+	}, "")
+	m := db.Migrator().(Migrator)
+	query := db.Table("meters").Select("`ts`,`current`,`location`").Where("`current` > ?", 10)
+	for _, run := range []func() error{
+		func() error { return m.CreateTopic("t_query", query) },
+		func() error { return m.CreateStableTopic("t_stable", "meters") },
+		func() error { return m.CreateDatabaseTopic("t_db", "power") },
+		func() error { return m.DropTopic("t_db") },
+	} {
+		if err := run(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	want := []string{
+		"CREATE TOPIC IF NOT EXISTS `t_query` AS SELECT `ts`,`current`,`location` FROM `meters` WHERE `current` > ?",
+		"CREATE TOPIC IF NOT EXISTS `t_stable` AS STABLE `meters`",
+		"CREATE TOPIC IF NOT EXISTS `t_db` AS DATABASE `power`",
+		"DROP TOPIC IF EXISTS `t_db`",
+	}
+	if len(queries) != len(want) {
+		t.Fatalf("expect queries %q, got: %q", want, queries)
+	}
+	for i := range want {
+		if queries[i] != want[i] {
+			t.Errorf("expect query: %s, got: %s", want[i], queries[i])
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package tdengine_gorm
+
+import (
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// UnionAll combine the queries with UNION ALL as the table of the outer query
+// [SELECT ... FROM ((query1) UNION ALL (query2) ...) AS `t`].
+// Each query keeps its own clauses, e.g. WINDOW and FILL, the outer ORDER BY, LIMIT and SLIMIT
+// apply to the combined result. The vars of the queries are bound in order.
+//
+//	UnionAll(db, q1, q2).Order("`ts` DESC").Limit(100).Find(&rows)
+func UnionAll(db *gorm.DB, queries ...*gorm.DB) *gorm.DB {
+	if len(queries) == 0 {
+		tx := db.Session(&gorm.Session{})
+		_ = tx.AddError(errors.New("tdengine: union all requires at least one query"))
+		return tx
+	}
+	parts := make([]string, 0, len(queries))
+	vars := make([]any, 0, len(queries))
+	for _, q := range queries {
+		parts = append(parts, "(?)")
+		vars = append(vars, q)
+	}
+	return db.Table("("+strings.Join(parts, " UNION ALL ")+") AS `t`", vars...)
+}
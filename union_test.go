@@ -0,0 +1,52 @@
+package tdengine_gorm
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thinkgos/tdengine-gorm/clause/fill"
+	"github.com/thinkgos/tdengine-gorm/clause/slimit"
+	"github.com/thinkgos/tdengine-gorm/clause/window"
+	"gorm.io/gorm"
+)
+
+func Test_UnionAll(t *testing.T) {
+	db, err := gorm.Open(&Dialect{DSN: dsnWithDb}, &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	minute := window.Duration{Value: 1, Unit: window.Minute}
+
+	q1 := db.Table("meters").
+		Select("_wstart AS `ts`,avg(`current`) AS `v`").
+		Where("`ts` >= ?", start).
+		Clauses(window.SetInterval(minute), fill.Fill{Type: fill.FillPrev})
+	q2 := db.Table("sensors").
+		Select("_wstart AS `ts`,avg(`temperature`) AS `v`").
+		Where("`location` = ?", "bj").
+		Clauses(window.SetInterval(minute), fill.SetValues(0))
+	stmt := UnionAll(db, q1, q2).
+		Order("`ts` DESC").
+		Clauses(slimit.SLimit{Limit: 2}).
+		Limit(10).
+		Find(&[]map[string]any{}).Statement
+	if stmt.Error != nil {
+		t.Fatalf("unexpected error: %v", stmt.Error)
+	}
+	want := "SELECT * FROM ((SELECT _wstart AS `ts`,avg(`current`) AS `v` FROM `meters` WHERE `ts` >= ? INTERVAL(1m) FILL (PREV)) UNION ALL " +
+		"(SELECT _wstart AS `ts`,avg(`temperature`) AS `v` FROM `sensors` WHERE `location` = ? INTERVAL(1m) FILL (VALUE,?))) AS `t` " +
+		"ORDER BY `ts` DESC SLIMIT 2 LIMIT ?"
+	if got := strings.TrimSpace(stmt.SQL.String()); got != want {
+		t.Errorf("expect sql: %s, got: %s", want, got)
+	}
+	if wantVars := []any{start, "bj", 0, 10}; !reflect.DeepEqual(stmt.Vars, wantVars) {
+		t.Errorf("expect vars: %v, got: %v", wantVars, stmt.Vars)
+	}
+
+	if err = UnionAll(db).Find(&[]map[string]any{}).Error; err == nil {
+		t.Errorf("expect error for empty queries")
+	}
+}
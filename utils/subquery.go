@@ -0,0 +1,18 @@
+package utils
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// SubqueryError the error of building the query as a subquery, e.g. db.Table("(?) AS t", query),
+// gorm builds the subquery in a dry run session and drops its error.
+func SubqueryError(query *gorm.DB) error {
+	if query == nil {
+		return nil
+	}
+	if query.Statement.SQL.Len() > 0 {
+		return query.Error
+	}
+	return query.Session(&gorm.Session{DryRun: true, Logger: logger.Discard}).Find(nil).Error
+}
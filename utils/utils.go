@@ -60,3 +60,9 @@ func Quote(s string) string {
 	QuoteTo(&builder, s)
 	return builder.String()
 }
+
+// QueryClauses the clauses of the query statement in build order, shared by the dialect and the clause tests.
+var QueryClauses = []string{"SELECT", "FROM", "JOIN", "WHERE", "PARTITION BY", "RANGE", "EVERY", "WINDOW", "FILL", "GROUP BY", "ORDER BY", "SLIMIT", "LIMIT"}
+
+// CreateClauses the clauses of the create statement in build order, shared by the dialect and the clause tests.
+var CreateClauses = []string{"CREATE TABLE", "INSERT", "USING", "VALUES", "ON CONFLICT"}